@@ -15,7 +15,7 @@
 package cobra
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 )
 
@@ -33,7 +33,7 @@ func legacyArgs(cmd *Command, args []string) error {
 
 	// root command with subcommands, do subcommand checking.
 	if !cmd.HasParent() && len(args) > 0 {
-		return fmt.Errorf("unknown command %q for %q%s", args[0], cmd.CommandPath(), cmd.findSuggestions(args[0]))
+		return errors.New(messagef(MsgUnknownCommand, args[0], cmd.CommandPath()) + cmd.findSuggestions(args[0]))
 	}
 	return nil
 }
@@ -41,13 +41,13 @@ func legacyArgs(cmd *Command, args []string) error {
 // NoArgs returns an error if any args are included.
 func NoArgs(cmd *Command, args []string) error {
 	if len(args) > 0 {
-		return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+		return errors.New(messagef(MsgUnknownCommand, args[0], cmd.CommandPath()))
 	}
 	return nil
 }
 
 // OnlyValidArgs returns an error if there are any positional args that are not in
-// the `ValidArgs` field of `Command`
+// the `ValidArgs` field of `Command`. The error suggests the closest valid args.
 func OnlyValidArgs(cmd *Command, args []string) error {
 	if len(cmd.ValidArgs) > 0 {
 		// Remove any description that may be included in ValidArgs.
@@ -58,7 +58,7 @@ func OnlyValidArgs(cmd *Command, args []string) error {
 		}
 		for _, v := range args {
 			if !stringInSlice(v, validArgs) {
-				return fmt.Errorf("invalid argument %q for %q%s", v, cmd.CommandPath(), cmd.findSuggestions(args[0]))
+				return errors.New(messagef(MsgInvalidArgument, v, cmd.CommandPath()) + cmd.findValueSuggestions(v, validArgs))
 			}
 		}
 	}
@@ -74,7 +74,7 @@ func ArbitraryArgs(cmd *Command, args []string) error {
 func MinimumNArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) < n {
-			return fmt.Errorf("requires at least %d arg(s), only received %d", n, len(args))
+			return errors.New(messagef(MsgArgsAtLeast, n, len(args)))
 		}
 		return nil
 	}
@@ -84,7 +84,7 @@ func MinimumNArgs(n int) PositionalArgs {
 func MaximumNArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) > n {
-			return fmt.Errorf("accepts at most %d arg(s), received %d", n, len(args))
+			return errors.New(messagef(MsgArgsAtMost, n, len(args)))
 		}
 		return nil
 	}
@@ -94,7 +94,7 @@ func MaximumNArgs(n int) PositionalArgs {
 func ExactArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) != n {
-			return fmt.Errorf("accepts %d arg(s), received %d", n, len(args))
+			return errors.New(messagef(MsgArgsExactly, n, len(args)))
 		}
 		return nil
 	}
@@ -104,7 +104,7 @@ func ExactArgs(n int) PositionalArgs {
 func RangeArgs(min int, max int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) < min || len(args) > max {
-			return fmt.Errorf("accepts between %d and %d arg(s), received %d", min, max, len(args))
+			return errors.New(messagef(MsgArgsBetween, min, max, len(args)))
 		}
 		return nil
 	}
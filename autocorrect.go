@@ -0,0 +1,148 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bufio"
+	"strings"
+	"time"
+)
+
+// AutocorrectMode defines what happens when a mistyped subcommand has a single suggestion.
+type AutocorrectMode int
+
+const (
+	// AutocorrectNever only prints the suggestions along with the error. This is the default.
+	AutocorrectNever AutocorrectMode = iota
+	// AutocorrectPrompt asks the user whether the suggested command should be run.
+	AutocorrectPrompt
+	// AutocorrectRun runs the suggested command after AutocorrectOptions.Delay.
+	AutocorrectRun
+)
+
+// AutocorrectOptions are the options to control the automatic correction of
+// mistyped subcommands, similar to git's help.autocorrect setting.
+type AutocorrectOptions struct {
+	// Mode selects the behavior when exactly one suggestion is found.
+	Mode AutocorrectMode
+	// Delay is the time, in deciseconds, to wait before running the
+	// suggested command in AutocorrectRun mode. Zero runs it immediately.
+	Delay int
+}
+
+// autocorrectSleep waits before running a corrected command.
+// It is a variable so that tests can override it.
+var autocorrectSleep = time.Sleep
+
+// autocorrect returns the args with the mistyped subcommand replaced by its
+// only suggestion, if autocorrection is enabled and the user agreed to it.
+// cmd, flags and err are the results of finding the command for args.
+func (c *Command) autocorrect(args []string, cmd *Command, flags []string, err error) ([]string, bool) {
+	opts := c.AutocorrectOptions
+	if opts.Mode == AutocorrectNever || cmd == nil {
+		return nil, false
+	}
+	// Never interfere with shell completion
+	if len(args) > 0 && (args[0] == ShellCompRequestCmd || args[0] == ShellCompNoDescRequestCmd) {
+		return nil, false
+	}
+	// Nobody can see what we are doing
+	if !isTerminal(c.InOrStdin()) {
+		return nil, false
+	}
+
+	if !cmd.HasSubCommands() || !cmd.suggestionsEnabled() {
+		return nil, false
+	}
+	argsWOflags := stripFlags(flags, cmd)
+	if len(argsWOflags) == 0 {
+		return nil, false
+	}
+	// A mistyped subcommand results in an error when looking for the command,
+	// in the help of the parent if it is not runnable, or in an error when
+	// validating its positional args.
+	if err == nil && cmd.Runnable() && cmd.ValidateArgs(argsWOflags) == nil {
+		return nil, false
+	}
+	typed := argsWOflags[0]
+
+	var suggestions []string
+	for _, s := range cmd.SuggestionsFor(typed) {
+		if !stringInSlice(s, suggestions) {
+			suggestions = append(suggestions, s)
+		}
+	}
+	if len(suggestions) != 1 {
+		return nil, false
+	}
+	suggestion := suggestions[0]
+
+	c.PrintErrln(messagef(MsgAutocorrectUnknown, cmd.CommandPath(), typed))
+	switch opts.Mode {
+	case AutocorrectPrompt:
+		c.PrintErr(messagef(MsgAutocorrectPrompt, suggestion))
+		answer, _ := bufio.NewReader(c.InOrStdin()).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return nil, false
+		}
+	case AutocorrectRun:
+		if opts.Delay > 0 {
+			delay := time.Duration(opts.Delay) * 100 * time.Millisecond
+			c.PrintErrln(messagef(MsgAutocorrectDelay, delay.Seconds(), suggestion))
+			autocorrectSleep(delay)
+		} else {
+			c.PrintErrln(messagef(MsgAutocorrectNow, suggestion))
+		}
+	default:
+		return nil, false
+	}
+
+	// The mistyped subcommand follows the names of the commands found in args
+	i := positionalArgIndex(args, cmd, len(stripFlags(args, cmd))-len(argsWOflags))
+	if i < 0 {
+		return nil, false
+	}
+	corrected := make([]string, len(args))
+	copy(corrected, args)
+	corrected[i] = suggestion
+	return corrected, true
+}
+
+// positionalArgIndex returns the index in args of the nth (from 0) arg that is
+// neither a flag nor a flag value, skipping them as stripFlags does, or -1.
+func positionalArgIndex(args []string, c *Command, n int) int {
+	c.mergePersistentFlags()
+	flags := c.Flags()
+
+	for pos := 0; pos < len(args); pos++ {
+		s := args[pos]
+		switch {
+		case s == "--":
+			return -1
+		case strings.HasPrefix(s, "--") && !strings.Contains(s, "=") && !hasNoOptDefVal(s[2:], flags):
+			fallthrough
+		case strings.HasPrefix(s, "-") && !strings.Contains(s, "=") && len(s) == 2 && !shortHasNoOptDefVal(s[1:], flags):
+			// Skip the value of the flag
+			pos++
+		case s != "" && !strings.HasPrefix(s, "-"):
+			if n == 0 {
+				return pos
+			}
+			n--
+		}
+	}
+	return -1
+}
@@ -0,0 +1,193 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setTerminal makes every stream look like a terminal, or none, for the duration of the test.
+// Colors are disabled, tests of colors must unset NO_COLOR.
+func setTerminal(t *testing.T, terminal bool) {
+	setEnv(t, "NO_COLOR", "1")
+	orig := isTerminal
+	isTerminal = func(interface{}) bool { return terminal }
+	t.Cleanup(func() { isTerminal = orig })
+}
+
+func newAutocorrectTestCommands(ran *string) *Command {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+	serverCmd := &Command{Use: "server", Run: func(cmd *Command, args []string) { *ran = cmd.CommandPath() + " " + strings.Join(args, " ") }}
+	remoteCmd := &Command{Use: "remote"}
+	addCmd := &Command{Use: "add", Run: func(cmd *Command, args []string) { *ran = cmd.CommandPath() + " " + strings.Join(args, " ") }}
+	remoteCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(serverCmd, remoteCmd)
+	return rootCmd
+}
+
+func executeAutocorrect(rootCmd *Command, stdin string, args ...string) (string, string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetArgs(args)
+	_, err := rootCmd.ExecuteC()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestAutocorrectRun(t *testing.T) {
+	setTerminal(t, true)
+	var slept time.Duration
+	origSleep := autocorrectSleep
+	autocorrectSleep = func(d time.Duration) { slept = d }
+	defer func() { autocorrectSleep = origSleep }()
+
+	var ran string
+	rootCmd := newAutocorrectTestCommands(&ran)
+	rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: AutocorrectRun, Delay: 15}
+
+	_, stderr, err := executeAutocorrect(rootCmd, "", "srever", "arg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ran != "root server arg" {
+		t.Errorf("Expected the corrected command to run, ran %q", ran)
+	}
+	if slept != 1500*time.Millisecond {
+		t.Errorf("Expected to wait 1.5s, waited %v", slept)
+	}
+	expected := "WARNING: You called a \"root\" command named \"srever\", which does not exist.\n" +
+		"Continuing in 1.5 seconds, assuming that you meant \"server\".\n"
+	if stderr != expected {
+		t.Errorf("Expected stderr %q, got %q", expected, stderr)
+	}
+}
+
+func TestAutocorrectRunImmediately(t *testing.T) {
+	setTerminal(t, true)
+
+	var ran string
+	rootCmd := newAutocorrectTestCommands(&ran)
+	rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: AutocorrectRun}
+
+	_, stderr, err := executeAutocorrect(rootCmd, "", "remote", "ad", "origin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ran != "root remote add origin" {
+		t.Errorf("Expected the corrected command to run, ran %q", ran)
+	}
+	checkStringContains(t, stderr, "Continuing under the assumption that you meant \"add\".")
+}
+
+func TestAutocorrectFlagValueEqualToTyped(t *testing.T) {
+	setTerminal(t, true)
+
+	var ran string
+	rootCmd := newAutocorrectTestCommands(&ran)
+	rootCmd.PersistentFlags().String("name", "", "name")
+	rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: AutocorrectRun}
+
+	_, _, err := executeAutocorrect(rootCmd, "", "--name", "srever", "srever", "arg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ran != "root server arg" {
+		t.Errorf("Expected the corrected command to run, ran %q", ran)
+	}
+	if name, _ := rootCmd.PersistentFlags().GetString("name"); name != "srever" {
+		t.Errorf("Expected the flag value to be kept, got %q", name)
+	}
+}
+
+func TestAutocorrectPrompt(t *testing.T) {
+	setTerminal(t, true)
+
+	var ran string
+	rootCmd := newAutocorrectTestCommands(&ran)
+	rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: AutocorrectPrompt}
+	_, stderr, err := executeAutocorrect(rootCmd, "y\n", "srever")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ran != "root server " {
+		t.Errorf("Expected the corrected command to run, ran %q", ran)
+	}
+	checkStringContains(t, stderr, "Run \"server\" instead [y/N]? ")
+
+	ran = ""
+	rootCmd = newAutocorrectTestCommands(&ran)
+	rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: AutocorrectPrompt}
+	_, stderr, err = executeAutocorrect(rootCmd, "n\n", "srever")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if ran != "" {
+		t.Errorf("Expected nothing to run, ran %q", ran)
+	}
+	checkStringContains(t, stderr, `Error: unknown command "srever" for "root"`)
+}
+
+func TestAutocorrectDisabled(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		terminal bool
+		mode     AutocorrectMode
+		args     []string
+	}{
+		{"never", true, AutocorrectNever, []string{"srever"}},
+		{"not a terminal", false, AutocorrectRun, []string{"srever"}},
+		{"several suggestions", true, AutocorrectRun, []string{"re"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			setTerminal(t, tc.terminal)
+
+			var ran string
+			rootCmd := newAutocorrectTestCommands(&ran)
+			rootCmd.AddCommand(&Command{Use: "reset", Run: emptyRun})
+			rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: tc.mode}
+			_, stderr, err := executeAutocorrect(rootCmd, "", tc.args...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if ran != "" {
+				t.Errorf("Expected nothing to run, ran %q", ran)
+			}
+			checkStringOmits(t, stderr, "WARNING")
+		})
+	}
+}
+
+func TestAutocorrectDuringCompletion(t *testing.T) {
+	setTerminal(t, true)
+
+	var ran string
+	rootCmd := newAutocorrectTestCommands(&ran)
+	rootCmd.AutocorrectOptions = AutocorrectOptions{Mode: AutocorrectRun}
+	_, stderr, err := executeAutocorrect(rootCmd, "", ShellCompNoDescRequestCmd, "srever", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, stderr, "WARNING")
+	if ran != "" {
+		t.Errorf("Expected nothing to run, ran %q", ran)
+	}
+}
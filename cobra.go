@@ -37,6 +37,9 @@ var templateFuncs = template.FuncMap{
 	"rpad":                    rpad,
 	"gt":                      Gt,
 	"eq":                      Eq,
+	"T":                       Message,
+	"wrapText":                wrapText,
+	"wrapIndent":              wrapIndent,
 }
 
 var initializers []func()
@@ -231,10 +234,10 @@ func stringInSlice(a string, list []string) bool {
 	return false
 }
 
-// CheckErr prints the msg with the prefix 'Error:' and exits with error code 1. If the msg is nil, it does nothing.
+// CheckErr prints the msg with the localized prefix 'Error:' and exits with error code 1. If the msg is nil, it does nothing.
 func CheckErr(msg interface{}) {
 	if msg != nil {
-		fmt.Fprintln(os.Stderr, "Error:", msg)
+		fmt.Fprintln(os.Stderr, Message(MsgErrorPrefix), msg)
 		os.Exit(1)
 	}
 }
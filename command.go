@@ -33,6 +33,7 @@ import (
 const (
 	FlagSetByCobraAnnotation     = "cobra_annotation_flag_set_by_cobra"
 	CommandDisplayNameAnnotation = "cobra_annotation_command_display_name"
+	FlagValidValuesAnnotation    = "cobra_annotation_flag_valid_values"
 
 	helpFlagName    = "help"
 	helpCommandName = "help"
@@ -82,6 +83,14 @@ type Command struct {
 	// Example is examples of how to use the command.
 	Example string
 
+	// Arguments describes the positional arguments of the command for its synopsis.
+	// If empty, the synopsis uses the arguments written in Use.
+	Arguments []Argument
+
+	// Examples are structured examples of how to use the command, shown after Example.
+	// Unlike Example, they can be checked with CheckExamples.
+	Examples []Example
+
 	// ValidArgs is list of all valid non-flag arguments that are accepted in shell completions
 	ValidArgs []Completion
 	// ValidArgsFunction is an optional function that provides valid non-flag arguments for shell completion.
@@ -179,6 +188,14 @@ type Command struct {
 	helpTemplate *tmplFunc
 	// helpFunc is help func defined by user.
 	helpFunc func(*Command, []string)
+	// theme is the theme set with SetTheme.
+	theme *Theme
+	// themeSet defines if a theme was set with SetTheme, possibly to nil.
+	themeSet bool
+	// revealed defines if the command is hidden but revealed by RevealHidden.
+	revealed bool
+	// textProvider resolves localized metadata, defined by user.
+	textProvider TextProvider
 	// helpCommand is command with usage 'help'. If it's not defined by user,
 	// cobra uses default help command.
 	helpCommand *Command
@@ -200,6 +217,10 @@ type Command struct {
 	outWriter io.Writer
 	// errWriter is a writer defined by the user that replaces stderr
 	errWriter io.Writer
+	// helpOutput is the writer the help is eventually written to, while it is
+	// rendered to another writer such as a buffer or a pager. It determines the
+	// width of the help.
+	helpOutput io.Writer
 
 	// FParseErrWhitelist flag parse errors to be ignored
 	FParseErrWhitelist FParseErrWhitelist
@@ -207,6 +228,25 @@ type Command struct {
 	// CompletionOptions is a set of options to control the handling of shell completion
 	CompletionOptions CompletionOptions
 
+	// AutocorrectOptions controls the automatic correction of mistyped subcommands.
+	// Only the options of the root command are used.
+	AutocorrectOptions AutocorrectOptions
+
+	// PagerOptions controls the use of a pager for the help and the output of commands.
+	// Only the options of the root command are used.
+	PagerOptions PagerOptions
+
+	// EnableHelpAll adds a hidden --help-all flag to all commands, showing the help
+	// with the hidden commands and flags. Setting the environment variable
+	// <PROGRAM>_HELP_ALL to true has the same effect on the help and on completion.
+	// Only the value of the root command is used.
+	EnableHelpAll bool
+
+	// UseSynopsis makes UseLine, and so the help and the docs, return the synopsis of
+	// the commands built by Synopsis instead of Use followed by [flags].
+	// Only the value of the root command is used.
+	UseSynopsis bool
+
 	// commandsAreSorted defines, if command slice are sorted or not.
 	commandsAreSorted bool
 	// commandCalledAs is the name or alias value used to call this command.
@@ -251,7 +291,7 @@ type Command struct {
 	DisableFlagsInUseLine bool
 
 	// DisableSuggestions disables the suggestions based on Levenshtein distance
-	// that go along with 'unknown command' messages.
+	// that go along with 'unknown command', 'unknown flag' and invalid value messages.
 	DisableSuggestions bool
 
 	// SuggestionsMinimumDistance defines minimum levenshtein distance to display suggestions.
@@ -514,19 +554,41 @@ func (c *Command) getHelpTemplateFunc() func(w io.Writer, data interface{}) erro
 	return defaultHelpFunc
 }
 
-// Help puts out the help for the command.
+// Help puts out the help for the command, through the pager if
+// PagerOptions.Help is set on the root command.
 // Used when a user calls help [command].
 // Can be defined by user by overriding HelpFunc.
 func (c *Command) Help() error {
-	c.HelpFunc()(c, []string{})
+	c.runHelpFunc([]string{})
 	return nil
 }
 
+// runHelpFunc calls the help function of the command, through the pager
+// if PagerOptions.Help is set on the root command. The flags which do not
+// apply given the flags already parsed are omitted.
+func (c *Command) runHelpFunc(args []string) {
+	if c.helpAllRequested() {
+		defer c.RevealHidden()()
+	}
+	defer c.hideInapplicableFlags()()
+	c.withPager(c.Root().PagerOptions.Help, func() {
+		if name := c.helpFlagTopic(); name != "" {
+			CheckErr(c.WriteFlagHelp(c.OutOrStdout(), name))
+			return
+		}
+		c.HelpFunc()(c, args)
+	})
+}
+
 // UsageString returns usage string.
 func (c *Command) UsageString() string {
 	// Storing normal writers
 	tmpOutput := c.outWriter
 	tmpErr := c.errWriter
+	tmpHelpOutput := c.helpOutput
+	if c.helpOutput == nil {
+		c.helpOutput = c.OutOrStdout()
+	}
 
 	bb := new(bytes.Buffer)
 	c.outWriter = bb
@@ -537,6 +599,7 @@ func (c *Command) UsageString() string {
 	// Setting things back to normal
 	c.outWriter = tmpOutput
 	c.errWriter = tmpErr
+	c.helpOutput = tmpHelpOutput
 
 	return bb.String()
 }
@@ -648,7 +711,7 @@ func (c *Command) ErrPrefix() string {
 	if c.HasParent() {
 		return c.parent.ErrPrefix()
 	}
-	return "Error:"
+	return Message(MsgErrorPrefix)
 }
 
 func hasNoOptDefVal(name string, fs *flag.FlagSet) bool {
@@ -779,20 +842,10 @@ func (c *Command) Find(args []string) (*Command, []string, error) {
 }
 
 func (c *Command) findSuggestions(arg string) string {
-	if c.DisableSuggestions {
+	if !c.suggestionsEnabled() {
 		return ""
 	}
-	if c.SuggestionsMinimumDistance <= 0 {
-		c.SuggestionsMinimumDistance = 2
-	}
-	var sb strings.Builder
-	if suggestions := c.SuggestionsFor(arg); len(suggestions) > 0 {
-		sb.WriteString("\n\nDid you mean this?\n")
-		for _, s := range suggestions {
-			_, _ = fmt.Fprintf(&sb, "\t%v\n", s)
-		}
-	}
-	return sb.String()
+	return formatSuggestions(c.SuggestionsFor(arg))
 }
 
 func (c *Command) findNext(next string) *Command {
@@ -908,17 +961,86 @@ func (c *Command) execute(a []string) (err error) {
 	}
 
 	if len(c.Deprecated) > 0 {
-		c.Printf("Command %q is deprecated, %s\n", c.Name(), c.Deprecated)
+		c.Println(messagef(MsgCommandDeprecated, c.Name(), c.Deprecated))
+	}
+
+	initialized := false
+	defer func() {
+		if initialized {
+			c.postRun()
+		}
+	}()
+	argWoFlags, run, err := c.prepareRun(a, func() {
+		initialized = true
+		c.preRun()
+	}, c.executePreRunHooks)
+	if !run {
+		return err
 	}
 
+	if c.RunE != nil {
+		if err := c.RunE(c, argWoFlags); err != nil {
+			return err
+		}
+	} else {
+		c.Run(c, argWoFlags)
+	}
+	if c.PostRunE != nil {
+		if err := c.PostRunE(c, argWoFlags); err != nil {
+			return err
+		}
+	} else if c.PostRun != nil {
+		c.PostRun(c, argWoFlags)
+	}
+	for p := c; p != nil; p = p.Parent() {
+		if p.PersistentPostRunE != nil {
+			if err := p.PersistentPostRunE(c, argWoFlags); err != nil {
+				return err
+			}
+			if !EnableTraverseRunHooks {
+				break
+			}
+		} else if p.PersistentPostRun != nil {
+			p.PersistentPostRun(c, argWoFlags)
+			if !EnableTraverseRunHooks {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepareRun runs the steps of execute which precede the Run functions: it
+// initializes the default flags, parses the flags, handles the help and version
+// flags, and validates the arguments and the flags. checkExample runs the same
+// steps without the hooks. initialize, if not nil, is called before the validation
+// of the arguments, and preRun, if not nil, between the validation of the
+// arguments and that of the flags, as the pre-run hooks may set flags.
+// It returns the arguments without the flags, and whether the command must run;
+// if not, err is the result of the execution, flag.ErrHelp if the help must be
+// printed instead.
+func (c *Command) prepareRun(a []string, initialize func(), preRun func(argWoFlags []string) error) (argWoFlags []string, run bool, err error) {
 	// initialize help and version flag at the last point possible to allow for user
 	// overriding
 	c.InitDefaultHelpFlag()
+	c.initHelpAllFlag()
 	c.InitDefaultVersionFlag()
 
+	if c.HasParent() && c == c.Parent().helpCommand {
+		if cmd, name := c.helpCmdFlagTopic(a); cmd != nil {
+			return nil, false, c.writeHelpCmdFlagHelp(cmd, name)
+		}
+	}
+
 	err = c.ParseFlags(a)
 	if err != nil {
-		return c.FlagErrorFunc()(c, err)
+		return nil, false, c.FlagErrorFunc()(c, c.flagErrorWithSuggestions(err))
+	}
+
+	argWoFlags = c.Flags().Args()
+	if c.DisableFlagParsing {
+		argWoFlags = a
 	}
 
 	// If help is called, regardless of other flags, return we want help.
@@ -928,11 +1050,14 @@ func (c *Command) execute(a []string) (err error) {
 		// should be impossible to get here as we always declare a help
 		// flag in InitDefaultHelpFlag()
 		c.Println("\"help\" flag declared as non-bool. Please correct your code")
-		return err
+		return argWoFlags, false, err
 	}
 
-	if helpVal {
-		return flag.ErrHelp
+	if name := c.helpFlagTopic(); name != "" && c.lookupFlagForHelp(name) == nil {
+		return argWoFlags, false, c.unknownFlagHelpError(name)
+	}
+	if helpVal || c.helpAllRequestedByFlag() {
+		return argWoFlags, false, flag.ErrHelp
 	}
 
 	// for back-compat, only add version flag behavior if version is defined
@@ -940,7 +1065,7 @@ func (c *Command) execute(a []string) (err error) {
 		versionVal, err := c.Flags().GetBool("version")
 		if err != nil {
 			c.Println("\"version\" flag declared as non-bool. Please correct your code")
-			return err
+			return argWoFlags, false, err
 		}
 		if versionVal {
 			fn := c.getVersionTemplateFunc()
@@ -948,27 +1073,42 @@ func (c *Command) execute(a []string) (err error) {
 			if err != nil {
 				c.Println(err)
 			}
-			return err
+			return argWoFlags, false, err
 		}
 	}
 
 	if !c.Runnable() {
-		return flag.ErrHelp
+		return argWoFlags, false, flag.ErrHelp
 	}
 
-	c.preRun()
+	if initialize != nil {
+		initialize()
+	}
 
-	defer c.postRun()
+	if err := c.ValidateArgs(argWoFlags); err != nil {
+		return argWoFlags, false, err
+	}
 
-	argWoFlags := c.Flags().Args()
-	if c.DisableFlagParsing {
-		argWoFlags = a
+	if preRun != nil {
+		if err := preRun(argWoFlags); err != nil {
+			return argWoFlags, false, err
+		}
 	}
 
-	if err := c.ValidateArgs(argWoFlags); err != nil {
-		return err
+	if err := c.ValidateRequiredFlags(); err != nil {
+		return argWoFlags, false, err
 	}
+	if err := c.ValidateFlagValues(); err != nil {
+		return argWoFlags, false, err
+	}
+	if err := c.ValidateFlagGroups(); err != nil {
+		return argWoFlags, false, err
+	}
+	return argWoFlags, true, nil
+}
 
+// executePreRunHooks calls the persistent pre-run hooks and the pre-run hook of c.
+func (c *Command) executePreRunHooks(argWoFlags []string) error {
 	parents := make([]*Command, 0, 5)
 	for p := c; p != nil; p = p.Parent() {
 		if EnableTraverseRunHooks {
@@ -1003,44 +1143,6 @@ func (c *Command) execute(a []string) (err error) {
 	} else if c.PreRun != nil {
 		c.PreRun(c, argWoFlags)
 	}
-
-	if err := c.ValidateRequiredFlags(); err != nil {
-		return err
-	}
-	if err := c.ValidateFlagGroups(); err != nil {
-		return err
-	}
-
-	if c.RunE != nil {
-		if err := c.RunE(c, argWoFlags); err != nil {
-			return err
-		}
-	} else {
-		c.Run(c, argWoFlags)
-	}
-	if c.PostRunE != nil {
-		if err := c.PostRunE(c, argWoFlags); err != nil {
-			return err
-		}
-	} else if c.PostRun != nil {
-		c.PostRun(c, argWoFlags)
-	}
-	for p := c; p != nil; p = p.Parent() {
-		if p.PersistentPostRunE != nil {
-			if err := p.PersistentPostRunE(c, argWoFlags); err != nil {
-				return err
-			}
-			if !EnableTraverseRunHooks {
-				break
-			}
-		} else if p.PersistentPostRun != nil {
-			p.PersistentPostRun(c, argWoFlags)
-			if !EnableTraverseRunHooks {
-				break
-			}
-		}
-	}
-
 	return nil
 }
 
@@ -1096,9 +1198,6 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		preExecHookFn(c)
 	}
 
-	// initialize help at the last point to allow for user overriding
-	c.InitDefaultHelpCmd()
-
 	args := c.args
 
 	// Workaround FAIL with "go test -v" or "cobra.test -test.v", see #155
@@ -1106,21 +1205,13 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		args = os.Args[1:]
 	}
 
-	// initialize the __complete command to be used for shell completion
-	c.initCompleteCmd(args)
-
-	// initialize the default completion command
-	c.InitDefaultCompletionCmd(args...)
-
-	// Now that all commands have been created, let's make sure all groups
-	// are properly created also
-	c.checkCommandGroups()
+	c.initDefaultCmds(args)
 
 	var flags []string
-	if c.TraverseChildren {
-		cmd, flags, err = c.Traverse(args)
-	} else {
-		cmd, flags, err = c.Find(args)
+	cmd, flags, err = c.findCommand(args)
+	if correctedArgs, ok := c.autocorrect(args, cmd, flags, err); ok {
+		args = correctedArgs
+		cmd, flags, err = c.findCommand(args)
 	}
 	if err != nil {
 		// If found parse to a subcommand and then failed, talk about the subcommand
@@ -1128,8 +1219,8 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 			c = cmd
 		}
 		if !c.SilenceErrors {
-			c.PrintErrln(c.ErrPrefix(), err.Error())
-			c.PrintErrf("Run '%v --help' for usage.\n", c.CommandPath())
+			c.PrintErrln(c.StyleErrorPrefix(c.ErrPrefix()), err.Error())
+			c.PrintErrln(messagef(MsgRunHelpForUsage, c.CommandPath()))
 		}
 		return c, err
 	}
@@ -1145,19 +1236,22 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		cmd.ctx = c.ctx
 	}
 
-	err = cmd.execute(flags)
+	pageOutput := c.PagerOptions.Output && cmd.Name() != ShellCompRequestCmd && cmd.Name() != ShellCompNoDescRequestCmd
+	cmd.withPager(pageOutput, func() {
+		err = cmd.execute(flags)
+	})
 	if err != nil {
 		// Always show help if requested, even if SilenceErrors is in
 		// effect
 		if errors.Is(err, flag.ErrHelp) {
-			cmd.HelpFunc()(cmd, args)
+			cmd.runHelpFunc(args)
 			return cmd, nil
 		}
 
 		// If root command has SilenceErrors flagged,
 		// all subcommands should respect it
 		if !cmd.SilenceErrors && !c.SilenceErrors {
-			c.PrintErrln(cmd.ErrPrefix(), err.Error())
+			c.PrintErrln(cmd.StyleErrorPrefix(cmd.ErrPrefix()), err.Error())
 		}
 
 		// If root command has SilenceUsage flagged,
@@ -1169,6 +1263,34 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 	return cmd, err
 }
 
+// initDefaultCmds adds the default commands to the root command c before the
+// command to execute is found in args, as ExecuteC and checkExample do.
+func (c *Command) initDefaultCmds(args []string) {
+	// initialize help at the last point to allow for user overriding
+	c.InitDefaultHelpCmd()
+
+	// initialize the __complete command to be used for shell completion
+	c.initCompleteCmd(args)
+
+	// initialize the __help-json command to be used by tools reading the help
+	c.initHelpJSONCmd(args)
+
+	// initialize the default completion command
+	c.InitDefaultCompletionCmd(args...)
+
+	// Now that all commands have been created, let's make sure all groups
+	// are properly created also
+	c.checkCommandGroups()
+}
+
+// findCommand finds the command to execute, as Find or Traverse depending on TraverseChildren.
+func (c *Command) findCommand(args []string) (*Command, []string, error) {
+	if c.TraverseChildren {
+		return c.Traverse(args)
+	}
+	return c.Find(args)
+}
+
 func (c *Command) ValidateArgs(args []string) error {
 	if c.Args == nil {
 		return ArbitraryArgs(c, args)
@@ -1195,7 +1317,7 @@ func (c *Command) ValidateRequiredFlags() error {
 	})
 
 	if len(missingFlagNames) > 0 {
-		return fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missingFlagNames, `", "`))
+		return errors.New(messagef(MsgRequiredFlagsNotSet, strings.Join(missingFlagNames, `", "`)))
 	}
 	return nil
 }
@@ -1219,14 +1341,12 @@ func (c *Command) checkCommandGroups() {
 func (c *Command) InitDefaultHelpFlag() {
 	c.mergePersistentFlags()
 	if c.Flags().Lookup(helpFlagName) == nil {
-		usage := "help for "
-		name := c.DisplayName()
-		if name == "" {
-			usage += "this command"
-		} else {
-			usage += name
+		usage := Message(MsgHelpFlagUsageNoName)
+		if name := c.DisplayName(); name != "" {
+			usage = messagef(MsgHelpFlagUsage, name)
 		}
-		c.Flags().BoolP(helpFlagName, "h", false, usage)
+		// The help flag is a boolean, which also accepts the name of a flag to show its extended help
+		c.Flags().VarPF(&helpFlagValue{cmd: c}, helpFlagName, "h", usage).NoOptDefVal = "true"
 		_ = c.Flags().SetAnnotation(helpFlagName, FlagSetByCobraAnnotation, []string{"true"})
 	}
 }
@@ -1242,11 +1362,9 @@ func (c *Command) InitDefaultVersionFlag() {
 
 	c.mergePersistentFlags()
 	if c.Flags().Lookup("version") == nil {
-		usage := "version for "
-		if c.Name() == "" {
-			usage += "this command"
-		} else {
-			usage += c.DisplayName()
+		usage := Message(MsgVersionFlagUsageNoName)
+		if c.Name() != "" {
+			usage = messagef(MsgVersionFlagUsage, c.DisplayName())
 		}
 		if c.Flags().ShorthandLookup("v") == nil {
 			c.Flags().BoolP("version", "v", false, usage)
@@ -1268,9 +1386,8 @@ func (c *Command) InitDefaultHelpCmd() {
 	if c.helpCommand == nil {
 		c.helpCommand = &Command{
 			Use:   "help [command]",
-			Short: "Help about any command",
-			Long: `Help provides help for any command in the application.
-Simply type ` + c.DisplayName() + ` help [path to command] for full details.`,
+			Short: Message(MsgHelpCmdShort),
+			Long:  messagef(MsgHelpCmdLong, c.DisplayName()),
 			ValidArgsFunction: func(c *Command, args []string, toComplete string) ([]Completion, ShellCompDirective) {
 				var completions []Completion
 				cmd, _, e := c.Root().Find(args)
@@ -1282,19 +1399,32 @@ Simply type ` + c.DisplayName() + ` help [path to command] for full details.`,
 					cmd = c.Root()
 				}
 				for _, subCmd := range cmd.Commands() {
-					if subCmd.IsAvailableCommand() || subCmd == cmd.helpCommand {
+					if subCmd.IsAvailableCommand() || subCmd.IsAdditionalHelpTopicCommand() || subCmd == cmd.helpCommand {
 						if strings.HasPrefix(subCmd.Name(), toComplete) {
-							completions = append(completions, CompletionWithDesc(subCmd.Name(), subCmd.Short))
+							completions = append(completions, CompletionWithDesc(subCmd.Name(), subCmd.DisplayShort()))
 						}
 					}
 				}
 				return completions, ShellCompDirectiveNoFileComp
 			},
 			Run: func(c *Command, args []string) {
+				if term, _ := c.Flags().GetString(helpSearchFlagName); term != "" {
+					term = strings.TrimSpace(term + " " + strings.Join(args, " "))
+					c.withPager(c.Root().PagerOptions.Help, func() {
+						c.Root().printSearchResults(c.OutOrStdout(), term)
+					})
+					return
+				}
 				cmd, _, e := c.Root().Find(args)
 				if cmd == nil || e != nil {
-					c.Printf("Unknown help topic %#q\n", args)
+					c.Println(messagef(MsgUnknownHelpTopic, args))
 					CheckErr(c.Root().Usage())
+				} else if c.helpTreeRequested() {
+					depth, _ := c.Flags().GetInt(helpDepthFlagName)
+					withFlags, _ := c.Flags().GetBool(helpFlagsFlagName)
+					c.withPager(c.Root().PagerOptions.Help, func() {
+						CheckErr(cmd.WriteTree(c.OutOrStdout(), TreeOptions{Depth: depth, Flags: withFlags}))
+					})
 				} else {
 					// FLow the context down to be used in help text
 					if cmd.ctx == nil {
@@ -1308,6 +1438,14 @@ Simply type ` + c.DisplayName() + ` help [path to command] for full details.`,
 			},
 			GroupID: c.helpCommandGroupID,
 		}
+		c.helpCommand.SetFlagErrorFunc(helpCmdFlagErrorFunc)
+		c.helpCommand.Flags().StringP(helpSearchFlagName, "k", "", Message(MsgHelpSearchFlagUsage))
+		_ = c.helpCommand.RegisterFlagCompletionFunc(helpSearchFlagName, NoFileCompletions)
+		c.helpCommand.Flags().Bool(helpTreeFlagName, false, Message(MsgHelpTreeFlagUsage))
+		c.helpCommand.Flags().Bool(helpAllFlagName, false, Message(MsgHelpAllFlagUsage))
+		c.helpCommand.Flags().Int(helpDepthFlagName, 0, Message(MsgHelpDepthFlagUsage))
+		c.helpCommand.Flags().Bool(helpFlagsFlagName, false, Message(MsgHelpFlagsFlagUsage))
+		_ = c.helpCommand.RegisterFlagCompletionFunc(helpDepthFlagName, NoFileCompletions)
 	}
 	c.RemoveCommand(c.helpCommand)
 	c.AddCommand(c.helpCommand)
@@ -1479,7 +1617,11 @@ func (c *Command) DisplayName() string {
 }
 
 // UseLine puts out the full usage for a given command (including parents).
+// If UseSynopsis is set on the root command, it returns Synopsis().
 func (c *Command) UseLine() string {
+	if c.Root().UseSynopsis {
+		return c.Synopsis()
+	}
 	var useline string
 	use := strings.Replace(c.Use, c.Name(), c.DisplayName(), 1)
 	if c.HasParent() {
@@ -1589,7 +1731,7 @@ func (c *Command) NameAndAliases() string {
 
 // HasExample determines if the command has example.
 func (c *Command) HasExample() bool {
-	return len(c.Example) > 0
+	return len(c.Example) > 0 || len(c.Examples) > 0
 }
 
 // Runnable determines if the command is itself runnable.
@@ -1939,41 +2081,47 @@ type tmplFunc struct {
 	fn   func(io.Writer, interface{}) error
 }
 
-var defaultUsageTemplate = `Usage:{{if .Runnable}}
+var defaultUsageTemplate = `{{.StyleHeading (T "usage")}}{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
   {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
 
-Aliases:
+{{.StyleHeading (T "aliases")}}
   {{.NameAndAliases}}{{end}}{{if .HasExample}}
 
-Examples:
-{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+{{.StyleHeading (T "examples")}}
+{{.DisplayExample}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
 
-Available Commands:{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+{{.StyleHeading (T "available_commands")}}{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{$.StyleCommand (rpad .Name .NamePadding)}} {{wrapIndent (len (printf "  %s " (rpad .Name .NamePadding))) $.HelpWidth .DisplayShort}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
 
-{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+{{$.StyleHeading ($.LocalizedGroupTitle $group)}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{$.StyleCommand (rpad .Name .NamePadding)}} {{wrapIndent (len (printf "  %s " (rpad .Name .NamePadding))) $.HelpWidth .DisplayShort}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
 
-Additional Commands:{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+{{.StyleHeading (T "additional_commands")}}{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+  {{$.StyleCommand (rpad .Name .NamePadding)}} {{wrapIndent (len (printf "  %s " (rpad .Name .NamePadding))) $.HelpWidth .DisplayShort}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
 
-Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+{{.StyleHeading (T "flags")}}
+{{(.DisplayFlags .LocalFlags).FlagUsagesWrapped .HelpWidth | trimTrailingWhitespaces | $.StyleFlags}}{{end}}{{if .HasAvailableInheritedFlags}}
 
-Global Flags:
-{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+{{.StyleHeading (T "global_flags")}}
+{{(.DisplayFlags .InheritedFlags).FlagUsagesWrapped .HelpWidth | trimTrailingWhitespaces | $.StyleFlags}}{{end}}{{if .HasHelpSubCommands}}
 
-Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
-  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+{{.StyleHeading (T "additional_help_topics")}}{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{$.StyleCommand (rpad .CommandPath .CommandPathPadding)}} {{wrapIndent (len (printf "  %s " (rpad .CommandPath .CommandPathPadding))) $.HelpWidth .DisplayShort}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
 
-Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+{{printf (T "more_information") .CommandPath | wrapText .HelpWidth}}{{end}}
 `
 
 // defaultUsageFunc is equivalent to executing defaultUsageTemplate. The two should be changed in sync.
 func defaultUsageFunc(w io.Writer, in interface{}) error {
 	c := in.(*Command)
-	fmt.Fprint(w, "Usage:")
+	width := c.HelpWidth()
+	// commandLine formats a command and its short description, padded to the given width
+	commandLine := func(name string, padding int, short string) string {
+		name = rpad(name, padding)
+		return fmt.Sprintf("\n  %s %s", c.StyleCommand(name), wrapIndent(len(name)+3, width, short))
+	}
+	fmt.Fprint(w, c.StyleHeading(Message(MsgUsage)))
 	if c.Runnable() {
 		fmt.Fprintf(w, "\n  %s", c.UseLine())
 	}
@@ -1981,76 +2129,76 @@ func defaultUsageFunc(w io.Writer, in interface{}) error {
 		fmt.Fprintf(w, "\n  %s [command]", c.CommandPath())
 	}
 	if len(c.Aliases) > 0 {
-		fmt.Fprintf(w, "\n\nAliases:\n")
+		fmt.Fprintf(w, "\n\n%s\n", c.StyleHeading(Message(MsgAliases)))
 		fmt.Fprintf(w, "  %s", c.NameAndAliases())
 	}
 	if c.HasExample() {
-		fmt.Fprintf(w, "\n\nExamples:\n")
-		fmt.Fprintf(w, "%s", c.Example)
+		fmt.Fprintf(w, "\n\n%s\n", c.StyleHeading(Message(MsgExamples)))
+		fmt.Fprintf(w, "%s", c.DisplayExample())
 	}
 	if c.HasAvailableSubCommands() {
 		cmds := c.Commands()
 		if len(c.Groups()) == 0 {
-			fmt.Fprintf(w, "\n\nAvailable Commands:")
+			fmt.Fprintf(w, "\n\n%s", c.StyleHeading(Message(MsgAvailableCommands)))
 			for _, subcmd := range cmds {
 				if subcmd.IsAvailableCommand() || subcmd.Name() == helpCommandName {
-					fmt.Fprintf(w, "\n  %s %s", rpad(subcmd.Name(), subcmd.NamePadding()), subcmd.Short)
+					fmt.Fprint(w, commandLine(subcmd.Name(), subcmd.NamePadding(), subcmd.DisplayShort()))
 				}
 			}
 		} else {
 			for _, group := range c.Groups() {
-				fmt.Fprintf(w, "\n\n%s", group.Title)
+				fmt.Fprintf(w, "\n\n%s", c.StyleHeading(c.LocalizedGroupTitle(group)))
 				for _, subcmd := range cmds {
 					if subcmd.GroupID == group.ID && (subcmd.IsAvailableCommand() || subcmd.Name() == helpCommandName) {
-						fmt.Fprintf(w, "\n  %s %s", rpad(subcmd.Name(), subcmd.NamePadding()), subcmd.Short)
+						fmt.Fprint(w, commandLine(subcmd.Name(), subcmd.NamePadding(), subcmd.DisplayShort()))
 					}
 				}
 			}
 			if !c.AllChildCommandsHaveGroup() {
-				fmt.Fprintf(w, "\n\nAdditional Commands:")
+				fmt.Fprintf(w, "\n\n%s", c.StyleHeading(Message(MsgAdditionalCommands)))
 				for _, subcmd := range cmds {
 					if subcmd.GroupID == "" && (subcmd.IsAvailableCommand() || subcmd.Name() == helpCommandName) {
-						fmt.Fprintf(w, "\n  %s %s", rpad(subcmd.Name(), subcmd.NamePadding()), subcmd.Short)
+						fmt.Fprint(w, commandLine(subcmd.Name(), subcmd.NamePadding(), subcmd.DisplayShort()))
 					}
 				}
 			}
 		}
 	}
 	if c.HasAvailableLocalFlags() {
-		fmt.Fprintf(w, "\n\nFlags:\n")
-		fmt.Fprint(w, trimRightSpace(c.LocalFlags().FlagUsages()))
+		fmt.Fprintf(w, "\n\n%s\n", c.StyleHeading(Message(MsgFlags)))
+		fmt.Fprint(w, c.StyleFlags(trimRightSpace(c.DisplayFlags(c.LocalFlags()).FlagUsagesWrapped(width))))
 	}
 	if c.HasAvailableInheritedFlags() {
-		fmt.Fprintf(w, "\n\nGlobal Flags:\n")
-		fmt.Fprint(w, trimRightSpace(c.InheritedFlags().FlagUsages()))
+		fmt.Fprintf(w, "\n\n%s\n", c.StyleHeading(Message(MsgGlobalFlags)))
+		fmt.Fprint(w, c.StyleFlags(trimRightSpace(c.DisplayFlags(c.InheritedFlags()).FlagUsagesWrapped(width))))
 	}
 	if c.HasHelpSubCommands() {
-		fmt.Fprintf(w, "\n\nAdditional help topics:")
+		fmt.Fprintf(w, "\n\n%s", c.StyleHeading(Message(MsgAdditionalHelpTopics)))
 		for _, subcmd := range c.Commands() {
 			if subcmd.IsAdditionalHelpTopicCommand() {
-				fmt.Fprintf(w, "\n  %s %s", rpad(subcmd.CommandPath(), subcmd.CommandPathPadding()), subcmd.Short)
+				fmt.Fprint(w, commandLine(subcmd.CommandPath(), subcmd.CommandPathPadding(), subcmd.DisplayShort()))
 			}
 		}
 	}
 	if c.HasAvailableSubCommands() {
-		fmt.Fprintf(w, "\n\nUse \"%s [command] --help\" for more information about a command.", c.CommandPath())
+		fmt.Fprintf(w, "\n\n%s", wrapText(width, messagef(MsgMoreInformation, c.CommandPath())))
 	}
 	fmt.Fprintln(w)
 	return nil
 }
 
-var defaultHelpTemplate = `{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
+var defaultHelpTemplate = `{{with (or .LocalizedLong .DisplayShort)}}{{. | trimTrailingWhitespaces | wrapText $.HelpWidth}}
 
 {{end}}{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}`
 
 // defaultHelpFunc is equivalent to executing defaultHelpTemplate. The two should be changed in sync.
 func defaultHelpFunc(w io.Writer, in interface{}) error {
 	c := in.(*Command)
-	usage := c.Long
+	usage := c.LocalizedLong()
 	if usage == "" {
-		usage = c.Short
+		usage = c.DisplayShort()
 	}
-	usage = trimRightSpace(usage)
+	usage = wrapText(c.HelpWidth(), trimRightSpace(usage))
 	if usage != "" {
 		fmt.Fprintln(w, usage)
 		fmt.Fprintln(w)
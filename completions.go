@@ -99,7 +99,6 @@ const (
 	// Constants for the completion command
 	compCmdName              = "completion"
 	compCmdNoDescFlagName    = "no-descriptions"
-	compCmdNoDescFlagDesc    = "disable completion descriptions"
 	compCmdNoDescFlagDefault = false
 )
 
@@ -419,9 +418,17 @@ func (c *Command) getCompletions(args []string) (*Command, []Completion, ShellCo
 	var completions []Completion
 	var directive ShellCompDirective
 
+	// Complete the hidden commands and flags too if <PROGRAM>_HELP_ALL is set
+	if finalCmd.helpAllRequested() {
+		defer finalCmd.RevealHidden()()
+	}
+
 	// Enforce flag groups before doing flag completions
 	finalCmd.enforceFlagGroupsForCompletion()
 
+	// Don't complete the flags which do not apply given the flags already parsed
+	defer finalCmd.hideInapplicableFlags()()
+
 	// Note that we want to perform flagname completion even if finalCmd.DisableFlagParsing==true;
 	// doing this allows for completion of persistent flag names even for commands that disable flag parsing.
 	//
@@ -444,7 +451,7 @@ func (c *Command) getCompletions(args []string) (*Command, []Completion, ShellCo
 				if !flag.Changed || acceptsMultiple {
 					// If the flag is not already present, or if it can be specified multiple times (Array, Slice, or stringTo)
 					// we suggest it as a completion
-					completions = append(completions, getFlagNameCompletions(flag, toComplete)...)
+					completions = append(completions, getFlagNameCompletions(finalCmd, flag, toComplete)...)
 				}
 			}
 
@@ -502,7 +509,7 @@ func (c *Command) getCompletions(args []string) (*Command, []Completion, ShellCo
 				for _, subCmd := range finalCmd.Commands() {
 					if subCmd.IsAvailableCommand() || subCmd == finalCmd.helpCommand {
 						if strings.HasPrefix(subCmd.Name(), toComplete) {
-							completions = append(completions, CompletionWithDesc(subCmd.Name(), subCmd.Short))
+							completions = append(completions, CompletionWithDesc(subCmd.Name(), subCmd.DisplayShort()))
 						}
 						directive = ShellCompDirectiveNoFileComp
 					}
@@ -555,6 +562,17 @@ func (c *Command) getCompletions(args []string) (*Command, []Completion, ShellCo
 	} else {
 		completionFn = finalCmd.ValidArgsFunction
 	}
+	if completionFn == nil && flag != nil && flagCompletion {
+		if validValues, present := flag.Annotations[FlagValidValuesAnnotation]; present {
+			// The flag only accepts a fixed set of values
+			for _, v := range validValues {
+				if strings.HasPrefix(v, toComplete) {
+					completions = append(completions, v)
+				}
+			}
+			directive = ShellCompDirectiveNoFileComp
+		}
+	}
 	if completionFn != nil {
 		// Go custom completion defined for this flag or command.
 		// Call the registered completion function to get the completions.
@@ -578,16 +596,21 @@ func helpOrVersionFlagPresent(cmd *Command) bool {
 	return false
 }
 
-func getFlagNameCompletions(flag *pflag.Flag, toComplete string) []Completion {
+func getFlagNameCompletions(cmd *Command, flag *pflag.Flag, toComplete string) []Completion {
 	if nonCompletableFlag(flag) {
 		return []Completion{}
 	}
 
 	var completions []Completion
+	usage := cmd.DisplayFlagUsage(flag)
+	if usage == "" {
+		// Only the first line of the extended help is used as a description
+		usage = cmd.LocalizedFlagLong(flag)
+	}
 	flagName := "--" + flag.Name
 	if strings.HasPrefix(flagName, toComplete) {
 		// Flag without the =
-		completions = append(completions, CompletionWithDesc(flagName, flag.Usage))
+		completions = append(completions, CompletionWithDesc(flagName, usage))
 
 		// Why suggest both long forms: --flag and --flag= ?
 		// This forces the user to *always* have to type either an = or a space after the flag name.
@@ -605,7 +628,7 @@ func getFlagNameCompletions(flag *pflag.Flag, toComplete string) []Completion {
 
 	flagName = "-" + flag.Shorthand
 	if len(flag.Shorthand) > 0 && strings.HasPrefix(flagName, toComplete) {
-		completions = append(completions, CompletionWithDesc(flagName, flag.Usage))
+		completions = append(completions, CompletionWithDesc(flagName, usage))
 	}
 
 	return completions
@@ -618,7 +641,7 @@ func completeRequireFlags(finalCmd *Command, toComplete string) []Completion {
 		if _, present := flag.Annotations[BashCompOneRequiredFlag]; present {
 			if !flag.Changed {
 				// If the flag is not already present, we suggest it as a completion
-				completions = append(completions, getFlagNameCompletions(flag, toComplete)...)
+				completions = append(completions, getFlagNameCompletions(finalCmd, flag, toComplete)...)
 			}
 		}
 	}
@@ -752,11 +775,9 @@ func (c *Command) InitDefaultCompletionCmd(args ...string) {
 	}
 
 	completionCmd := &Command{
-		Use:   compCmdName,
-		Short: "Generate the autocompletion script for the specified shell",
-		Long: fmt.Sprintf(`Generate the autocompletion script for %[1]s for the specified shell.
-See each sub-command's help for details on how to use the generated script.
-`, c.Root().Name()),
+		Use:               compCmdName,
+		Short:             Message(MsgCompletionCmdShort),
+		Long:              messagef(MsgCompletionCmdLong, c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		Hidden:            c.CompletionOptions.HiddenDefaultCmd,
@@ -782,31 +803,11 @@ See each sub-command's help for details on how to use the generated script.
 
 	out := c.OutOrStdout()
 	noDesc := c.CompletionOptions.DisableDescriptions
-	shortDesc := "Generate the autocompletion script for %s"
+	noDescFlagDesc := Message(MsgCompletionNoDescFlag)
 	bash := &Command{
-		Use:   "bash",
-		Short: fmt.Sprintf(shortDesc, "bash"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for the bash shell.
-
-This script depends on the 'bash-completion' package.
-If it is not installed already, you can install it via your OS's package manager.
-
-To load completions in your current shell session:
-
-	source <(%[1]s completion bash)
-
-To load completions for every new session, execute once:
-
-#### Linux:
-
-	%[1]s completion bash > /etc/bash_completion.d/%[1]s
-
-#### macOS:
-
-	%[1]s completion bash > $(brew --prefix)/etc/bash_completion.d/%[1]s
-
-You will need to start a new shell for this setup to take effect.
-`, c.Root().Name()),
+		Use:                   "bash",
+		Short:                 messagef(MsgCompletionShellShort, "bash"),
+		Long:                  messagef(MsgCompletionBashLong, c.Root().Name()),
 		Args:                  NoArgs,
 		DisableFlagsInUseLine: true,
 		ValidArgsFunction:     NoFileCompletions,
@@ -815,35 +816,13 @@ You will need to start a new shell for this setup to take effect.
 		},
 	}
 	if haveNoDescFlag {
-		bash.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		bash.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, noDescFlagDesc)
 	}
 
 	zsh := &Command{
-		Use:   "zsh",
-		Short: fmt.Sprintf(shortDesc, "zsh"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for the zsh shell.
-
-If shell completion is not already enabled in your environment you will need
-to enable it.  You can execute the following once:
-
-	echo "autoload -U compinit; compinit" >> ~/.zshrc
-
-To load completions in your current shell session:
-
-	source <(%[1]s completion zsh)
-
-To load completions for every new session, execute once:
-
-#### Linux:
-
-	%[1]s completion zsh > "${fpath[1]}/_%[1]s"
-
-#### macOS:
-
-	%[1]s completion zsh > $(brew --prefix)/share/zsh/site-functions/_%[1]s
-
-You will need to start a new shell for this setup to take effect.
-`, c.Root().Name()),
+		Use:               "zsh",
+		Short:             messagef(MsgCompletionShellShort, "zsh"),
+		Long:              messagef(MsgCompletionZshLong, c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		RunE: func(cmd *Command, args []string) error {
@@ -854,24 +833,13 @@ You will need to start a new shell for this setup to take effect.
 		},
 	}
 	if haveNoDescFlag {
-		zsh.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		zsh.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, noDescFlagDesc)
 	}
 
 	fish := &Command{
-		Use:   "fish",
-		Short: fmt.Sprintf(shortDesc, "fish"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for the fish shell.
-
-To load completions in your current shell session:
-
-	%[1]s completion fish | source
-
-To load completions for every new session, execute once:
-
-	%[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
-
-You will need to start a new shell for this setup to take effect.
-`, c.Root().Name()),
+		Use:               "fish",
+		Short:             messagef(MsgCompletionShellShort, "fish"),
+		Long:              messagef(MsgCompletionFishLong, c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		RunE: func(cmd *Command, args []string) error {
@@ -879,21 +847,13 @@ You will need to start a new shell for this setup to take effect.
 		},
 	}
 	if haveNoDescFlag {
-		fish.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		fish.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, noDescFlagDesc)
 	}
 
 	powershell := &Command{
-		Use:   "powershell",
-		Short: fmt.Sprintf(shortDesc, "powershell"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for powershell.
-
-To load completions in your current shell session:
-
-	%[1]s completion powershell | Out-String | Invoke-Expression
-
-To load completions for every new session, add the output of the above command
-to your powershell profile.
-`, c.Root().Name()),
+		Use:               "powershell",
+		Short:             messagef(MsgCompletionShellShort, "powershell"),
+		Long:              messagef(MsgCompletionPwshLong, c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		RunE: func(cmd *Command, args []string) error {
@@ -905,7 +865,7 @@ to your powershell profile.
 		},
 	}
 	if haveNoDescFlag {
-		powershell.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		powershell.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, noDescFlagDesc)
 	}
 
 	completionCmd.AddCommand(bash, zsh, fish, powershell)
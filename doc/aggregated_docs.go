@@ -0,0 +1,305 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cpuguy83/go-md2man/v2/md2man"
+	"github.com/spf13/cobra"
+)
+
+// reSTSectionChars are the characters underlining the titles of the sections of
+// the aggregated ReST documents, by level.
+var reSTSectionChars = []string{"=", "-", "~", "^", "\"", "'"}
+
+// aggregatedCommand is a command of an aggregated document, with its depth below
+// the command of the document.
+type aggregatedCommand struct {
+	cmd   *cobra.Command
+	depth int
+}
+
+// aggregatedCommands returns cmd and its documented descendants, depth first.
+func aggregatedCommands(cmd *cobra.Command, depth int) []aggregatedCommand {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+
+	commands := []aggregatedCommand{{cmd, depth}}
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+	for _, child := range children {
+		if isDocumented(child) {
+			commands = append(commands, aggregatedCommands(child, depth+1)...)
+		}
+	}
+	return commands
+}
+
+// documentedChildren returns the documented subcommands of cmd, sorted by name.
+func documentedChildren(cmd *cobra.Command) []*cobra.Command {
+	var result []*cobra.Command
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+	for _, child := range children {
+		if isDocumented(child) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+func commandRef(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_")
+}
+
+// GenMarkdownAggregated writes a single markdown document for cmd and all its
+// descendants. The section of each command is nested in the section of its parent,
+// with a heading one level lower, down to level 5 so that its Examples, Options and
+// Commands headings stay one level lower, and starts with an anchor named after the
+// command, e.g. "app_get", to which the lists of subcommands link.
+func GenMarkdownAggregated(cmd *cobra.Command, w io.Writer) error {
+	buf := new(bytes.Buffer)
+	for _, c := range aggregatedCommands(cmd, 0) {
+		writeMarkdownAggregatedCommand(buf, c.cmd, c.depth)
+	}
+	if !autoGenTagDisabled(cmd) {
+		tag, err := autoGenTag()
+		if err != nil {
+			return err
+		}
+		buf.WriteString("###### " + tag + "\n")
+	}
+	_, err := io.WriteString(w, cobra.StripANSI(buf.String()))
+	return err
+}
+
+func writeMarkdownAggregatedCommand(buf *bytes.Buffer, cmd *cobra.Command, depth int) {
+	level := minInt(depth+1, 5)
+	heading := strings.Repeat("#", level)
+	subheading := strings.Repeat("#", level+1)
+
+	buf.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n\n", commandRef(cmd)))
+	buf.WriteString(heading + " " + cmd.CommandPath() + "\n\n")
+	if short := cmd.DisplayShort(); len(short) > 0 {
+		buf.WriteString(short + "\n\n")
+	}
+	topic, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]
+	if isTopic {
+		buf.WriteString(shiftMarkdownHeadings(topic, level) + "\n\n")
+	} else if long := cmd.LocalizedLong(); len(long) > 0 {
+		buf.WriteString(long + "\n\n")
+	}
+	if cmd.Runnable() {
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", cmd.UseLine()))
+	}
+	if example := cmd.DisplayExample(); len(example) > 0 {
+		buf.WriteString(subheading + " Examples\n\n")
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", example))
+	}
+	if flags := cmd.DisplayFlags(cmd.NonInheritedFlags()); !isTopic && flags.HasAvailableFlags() {
+		buf.WriteString(subheading + " Options\n\n")
+		buf.WriteString("```\n" + flags.FlagUsages() + "```\n\n")
+	}
+	if children := documentedChildren(cmd); len(children) > 0 {
+		buf.WriteString(subheading + " Commands\n\n")
+		for _, child := range children {
+			buf.WriteString(fmt.Sprintf("* [%s](#%s)\t - %s\n", child.CommandPath(), commandRef(child), child.DisplayShort()))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// GenReSTAggregated writes a single reStructuredText document for cmd and all its
+// descendants. The section of each command is nested in the section of its parent
+// and has a target named after the command, e.g. "app_get", to which the lists of
+// subcommands link. The markdown of the help topics is converted to reStructuredText.
+func GenReSTAggregated(cmd *cobra.Command, w io.Writer) error {
+	buf := new(bytes.Buffer)
+	for _, c := range aggregatedCommands(cmd, 0) {
+		writeReSTAggregatedCommand(buf, c.cmd, c.depth)
+	}
+	if !autoGenTagDisabled(cmd) {
+		tag, err := autoGenTag()
+		if err != nil {
+			return err
+		}
+		buf.WriteString("*" + tag + "*\n")
+	}
+	_, err := io.WriteString(w, cobra.StripANSI(buf.String()))
+	return err
+}
+
+func writeReSTAggregatedCommand(buf *bytes.Buffer, cmd *cobra.Command, depth int) {
+	level := minInt(depth, len(reSTSectionChars)-2)
+	title := func(s string, level int) {
+		buf.WriteString(s + "\n" + strings.Repeat(reSTSectionChars[level], len(s)) + "\n\n")
+	}
+
+	buf.WriteString(".. _" + commandRef(cmd) + ":\n\n")
+	title(cmd.CommandPath(), level)
+	if short := cmd.DisplayShort(); len(short) > 0 {
+		buf.WriteString(short + "\n\n")
+	}
+	topic, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]
+	if isTopic {
+		buf.WriteString(reSTTopic(topic, level) + "\n\n")
+	} else if long := cmd.LocalizedLong(); len(long) > 0 {
+		buf.WriteString(long + "\n\n")
+	}
+	if cmd.Runnable() {
+		buf.WriteString(fmt.Sprintf("::\n\n  %s\n\n", cmd.UseLine()))
+	}
+	if example := cmd.DisplayExample(); len(example) > 0 {
+		title("Examples", level+1)
+		buf.WriteString(fmt.Sprintf("::\n\n%s\n\n", indentString(example, "  ")))
+	}
+	if flags := cmd.DisplayFlags(cmd.NonInheritedFlags()); !isTopic && flags.HasAvailableFlags() {
+		title("Options", level+1)
+		buf.WriteString("::\n\n" + flags.FlagUsages() + "\n")
+	}
+	if children := documentedChildren(cmd); len(children) > 0 {
+		title("Commands", level+1)
+		for _, child := range children {
+			buf.WriteString(fmt.Sprintf("* `%s <%s_>`_ \t - %s\n", child.CommandPath(), commandRef(child), child.DisplayShort()))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// reSTTopic converts the markdown src of a help topic to reStructuredText nested in
+// a section of the given level: its headings become the titles of subsections, down
+// to the last level of reSTSectionChars, and its code blocks literal blocks.
+func reSTTopic(src string, level int) string {
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(strings.TrimSpace(src), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			if !inFence {
+				lines = append(lines, "::", "")
+			} else {
+				lines = append(lines, "")
+			}
+			inFence = !inFence
+		case inFence:
+			lines = append(lines, strings.TrimRight("  "+line, " "))
+		case strings.HasPrefix(line, "#"):
+			text := strings.TrimLeft(line, "#")
+			sub := minInt(level+len(line)-len(text), len(reSTSectionChars)-1)
+			text = strings.TrimSpace(text)
+			lines = append(lines, text, strings.Repeat(reSTSectionChars[sub], len(text)))
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// GenManAggregated writes a single man page for cmd and all its descendants, with
+// the subcommands described in subsections of its COMMANDS section, as the man
+// pages of git. The header argument may be nil.
+func GenManAggregated(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
+	if header == nil {
+		header = &GenManHeader{}
+	}
+	if err := fillHeader(header, cmd.CommandPath(), cmd.DisableAutoGenTag); err != nil {
+		return err
+	}
+
+	commands := aggregatedCommands(cmd, 0)
+	buf := new(bytes.Buffer)
+	manPreamble(buf, header, cmd, strings.ReplaceAll(cmd.CommandPath(), " ", "-"))
+	if _, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]; !isTopic {
+		manPrintOptions(buf, cmd, header)
+	}
+	if len(commands) > 1 {
+		buf.WriteString("# COMMANDS\n")
+		for _, c := range commands[1:] {
+			writeManAggregatedCommand(buf, c.cmd, header)
+		}
+	}
+	manPrintSection(buf, cmd, "EXIT STATUS", ManExitStatusAnnotation, header.ExitStatus)
+	manPrintEnvironment(buf, cmd, header)
+	manPrintSection(buf, cmd, "FILES", ManFilesAnnotation, header.Files)
+	manPrintSection(buf, cmd, "BUGS", ManBugsAnnotation, header.Bugs)
+	if example := cmd.DisplayExample(); len(example) > 0 {
+		buf.WriteString("# EXAMPLE\n")
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n", example))
+	}
+	manPrintSection(buf, cmd, "AUTHORS", ManAuthorsAnnotation, header.Authors)
+	if !autoGenTagDisabled(cmd) {
+		buf.WriteString(fmt.Sprintf("# HISTORY\n%s Auto generated by spf13/cobra\n", header.Date.Format("2-Jan-2006")))
+	}
+
+	_, err := w.Write(md2man.Render([]byte(cobra.StripANSI(buf.String()))))
+	return err
+}
+
+func writeManAggregatedCommand(buf *bytes.Buffer, cmd *cobra.Command, header *GenManHeader) {
+	buf.WriteString("### " + cmd.CommandPath() + "\n")
+	topic, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]
+	if !isTopic {
+		buf.WriteString(fmt.Sprintf("**%s**\n\n", cmd.UseLine()))
+	}
+	description := cmd.LocalizedLong()
+	if len(description) == 0 {
+		description = cmd.DisplayShort()
+	}
+	if isTopic {
+		// The headings of the topic can't be nested below a subsection
+		description = stripMarkdownHeadings(topic)
+	}
+	if len(description) > 0 {
+		buf.WriteString(description + "\n\n")
+	}
+	if flags := docFlags(cmd, cmd.NonInheritedFlags(), header.FlagMetadata); !isTopic && flags.HasAvailableFlags() {
+		buf.WriteString("**Options**\n\n")
+		manPrintFlags(buf, flags)
+	}
+	if example := cmd.DisplayExample(); len(example) > 0 {
+		buf.WriteString("**Example**\n\n")
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", example))
+	}
+}
+
+// stripMarkdownHeadings turns the headings of the markdown src, outside of its
+// code blocks, into bold paragraphs.
+func stripMarkdownHeadings(src string) string {
+	lines := strings.Split(src, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case !inFence && strings.HasPrefix(line, "#"):
+			lines[i] = "**" + strings.TrimSpace(strings.TrimLeft(line, "#")) + "**"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,162 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/cobra"
+)
+
+func newAggregatedCmd() *cobra.Command {
+	root := &cobra.Command{Use: "app", Short: "the app", Run: emptyRun, DisableAutoGenTag: true}
+	get := &cobra.Command{Use: "get", Short: "get things", Example: "app get pods", Run: emptyRun}
+	get.Flags().Int("limit", 10, "the limit")
+	pods := &cobra.Command{Use: "pods", Short: "get pods", Long: "Get the pods.", Run: emptyRun}
+	hidden := &cobra.Command{Use: "internal", Short: "an internal command", Hidden: true, Run: emptyRun}
+	get.AddCommand(pods)
+	root.AddCommand(get, hidden)
+	return root
+}
+
+func TestGenMarkdownAggregated(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenMarkdownAggregated(newAggregatedCmd(), buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "<a id=\"app\"></a>\n\n# app\n\nthe app\n")
+	checkStringContains(t, output, "## Commands\n\n* [app get](#app_get)\t - get things\n")
+	checkStringContains(t, output, "<a id=\"app_get\"></a>\n\n## app get\n")
+	checkStringContains(t, output, "### Examples\n\n```\napp get pods\n```\n")
+	checkStringContains(t, output, "### Options\n\n```\n  -h, --help        help for get\n      --limit int   the limit (default 10)\n```\n")
+	checkStringContains(t, output, "* [app get pods](#app_get_pods)\t - get pods\n")
+	checkStringContains(t, output, "<a id=\"app_get_pods\"></a>\n\n### app get pods\n\nget pods\n\nGet the pods.\n")
+	checkStringOmits(t, output, "internal")
+	checkStringOmits(t, output, "Auto generated")
+}
+
+func TestGenMarkdownAggregatedHeadingLevels(t *testing.T) {
+	root := &cobra.Command{Use: "l0", Run: emptyRun}
+	parent := root
+	for _, name := range []string{"l1", "l2", "l3", "l4", "l5", "l6", "l7"} {
+		child := &cobra.Command{Use: name, Run: emptyRun}
+		parent.AddCommand(child)
+		parent = child
+	}
+	parent.AddCommand(&cobra.Command{Use: "l8", Run: emptyRun})
+	topics := fstest.MapFS{
+		"topics/deep.md": {Data: []byte("# Deep topic\n\n#### Details\n\nText.\n")},
+	}
+	if err := parent.AddHelpTopics(topics, "topics"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := GenMarkdownAggregated(root, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "\n#### l0 l1 l2 l3\n")
+	checkStringContains(t, output, "\n##### l0 l1 l2 l3 l4\n")
+	checkStringContains(t, output, "\n##### l0 l1 l2 l3 l4 l5\n")
+	checkStringContains(t, output, "\n##### l0 l1 l2 l3 l4 l5 l6 l7 l8\n")
+	checkStringContains(t, output, "\n###### Commands\n\n* [l0 l1 l2 l3 l4 l5 l6](#l0_l1_l2_l3_l4_l5_l6)")
+	checkStringContains(t, output, "\n##### l0 l1 l2 l3 l4 l5 l6 l7 deep\n\nDeep topic\n\n###### Details\n")
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "#######") {
+			t.Errorf("Expected no heading below level 6, got %q", line)
+		}
+	}
+}
+
+func TestShiftMarkdownHeadings(t *testing.T) {
+	src := "# Title\n\n```\n# comment\n```\n### Section"
+	expected := "### Title\n\n```\n# comment\n```\n##### Section"
+	if got := shiftMarkdownHeadings(src, 2); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	expected = "###### Title\n\n```\n# comment\n```\n###### Section"
+	if got := shiftMarkdownHeadings(src, 5); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestGenReSTAggregated(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenReSTAggregated(newAggregatedCmd(), buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, ".. _app:\n\napp\n===\n\nthe app\n")
+	checkStringContains(t, output, "Commands\n--------\n\n* `app get <app_get_>`_ \t - get things\n")
+	checkStringContains(t, output, ".. _app_get:\n\napp get\n-------\n")
+	checkStringContains(t, output, "Options\n~~~~~~~\n\n::\n\n  -h, --help        help for get\n")
+	checkStringContains(t, output, ".. _app_get_pods:\n\napp get pods\n~~~~~~~~~~~~\n")
+	checkStringContains(t, output, "Options\n^^^^^^^\n")
+	checkStringOmits(t, output, "internal")
+}
+
+func TestGenReSTAggregatedHelpTopic(t *testing.T) {
+	root := &cobra.Command{Use: "app", Short: "the app", Run: emptyRun, DisableAutoGenTag: true}
+	topics := fstest.MapFS{
+		"topics/environment.md": {Data: []byte("# Environment variables\n\nThe variables.\n\n## Home\n\nSet it:\n\n```\nAPP_HOME=/tmp app\n```\n")},
+	}
+	if err := root.AddHelpTopics(topics, "topics"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := GenReSTAggregated(root, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, ".. _app_environment:\n\napp environment\n---------------\n\nEnvironment variables\n\nThe variables.\n\n"+
+		"Home\n^^^^\n\nSet it:\n\n::\n\n  APP_HOME=/tmp app\n\n")
+	checkStringOmits(t, output, "```")
+	checkStringOmits(t, output, "#")
+}
+
+func TestGenManAggregated(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenManAggregated(newAggregatedCmd(), &GenManHeader{Section: "1"}, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, ".TH \"APP\" \"1\"")
+	checkStringContains(t, output, ".SH COMMANDS\n.SS app get\n\\fBapp get [flags]\\fP\n")
+	checkStringContains(t, output, "\\fB--limit\\fP=10\n\tthe limit\n")
+	checkStringContains(t, output, ".EX\napp get pods\n.EE\n")
+	checkStringContains(t, output, ".SS app get pods\n\\fBapp get pods [flags]\\fP\n\n.PP\nGet the pods.\n")
+	checkStringOmits(t, output, "internal")
+	checkStringOmits(t, output, ".SH SEE ALSO")
+	checkStringOmits(t, output, ".SH HISTORY")
+}
+
+func TestStripMarkdownHeadings(t *testing.T) {
+	src := "# Title\n\nText\n\n```\n# comment\n```\n## Section"
+	expected := "**Title**\n\nText\n\n```\n# comment\n```\n**Section**"
+	if got := stripMarkdownHeadings(src); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const asciidocExtension = ".adoc"
+
+// asciidocEscapes replaces the characters of the inline AsciiDoc syntax with
+// attribute references or passthroughs, so that text is shown as is.
+var asciidocEscapes = strings.NewReplacer(
+	"*", "{asterisk}",
+	"`", "{backtick}",
+	"^", "{caret}",
+	"~", "{tilde}",
+	"+", "{plus}",
+	"[", "{startsb}",
+	"]", "{endsb}",
+	"|", "{vbar}",
+	"\\", "{backslash}",
+	"{", "++{++",
+	"_", "++_++",
+	"#", "++#++",
+	"<", "++<++",
+	">", "++>++",
+	"&", "++&++",
+)
+
+// escapeAsciidoc escapes text to be shown as is in AsciiDoc. Lines which would
+// start a block, e.g. a title or a list, are escaped too.
+func escapeAsciidoc(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = asciidocEscapes.Replace(line)
+		if len(line) > 0 && strings.ContainsRune("=-./:'", rune(line[0])) {
+			line = "{empty}" + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultAsciidocLinkHandler links to the page of a command with an xref, as expected by Antora.
+func defaultAsciidocLinkHandler(name, ref string) string {
+	return fmt.Sprintf("xref:%s%s[%s]", ref, asciidocExtension, name)
+}
+
+// GenAsciidoc creates AsciiDoc output.
+func GenAsciidoc(cmd *cobra.Command, w io.Writer) error {
+	return GenAsciidocCustom(cmd, w, defaultAsciidocLinkHandler)
+}
+
+// GenAsciidocCustom creates custom AsciiDoc output. linkHandler returns the link
+// to the page of a command, given its path and its reference, e.g. "app_get".
+func GenAsciidocCustom(cmd *cobra.Command, w io.Writer, linkHandler func(name, ref string) string) error {
+	return genAsciidoc(cmd, w, linkHandler, nil)
+}
+
+// GenAsciidocMan creates AsciiDoc output with the manpage doctype, from which
+// Asciidoctor generates man pages. The header argument may be nil; its Title is
+// not used, as the title of the page is the name of the command and the section.
+func GenAsciidocMan(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
+	if header == nil {
+		header = &GenManHeader{}
+	}
+	if err := fillHeader(header, cmd.CommandPath(), autoGenTagDisabled(cmd)); err != nil {
+		return err
+	}
+	return genAsciidoc(cmd, w, nil, header)
+}
+
+func genAsciidoc(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string, header *GenManHeader) error {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+
+	buf := new(bytes.Buffer)
+	name := cmd.CommandPath()
+	dashedName := strings.ReplaceAll(name, " ", "-")
+	short := cmd.DisplayShort()
+	long := cmd.LocalizedLong()
+	_, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]
+
+	if header != nil {
+		buf.WriteString(fmt.Sprintf("= %s(%s)\n", dashedName, header.Section))
+		buf.WriteString(":doctype: manpage\n")
+		if header.Manual != "" {
+			buf.WriteString(":manmanual: " + header.Manual + "\n")
+		}
+		if header.Source != "" {
+			buf.WriteString(":mansource: " + header.Source + "\n")
+		}
+		buf.WriteString(":revdate: " + header.date + "\n\n")
+		buf.WriteString("== NAME\n\n")
+		buf.WriteString(dashedName + " - " + escapeAsciidoc(short) + "\n\n")
+		if !isTopic {
+			buf.WriteString("== SYNOPSIS\n\n")
+			buf.WriteString("*" + escapeAsciidoc(cmd.UseLine()) + "*\n\n")
+		}
+		buf.WriteString("== DESCRIPTION\n\n")
+		if len(long) == 0 {
+			long = short
+		}
+		buf.WriteString(escapeAsciidoc(long) + "\n\n")
+	} else {
+		buf.WriteString("[[" + strings.ReplaceAll(name, " ", "_") + "]]\n")
+		buf.WriteString("= " + escapeAsciidoc(name) + "\n\n")
+		if len(short) > 0 {
+			buf.WriteString(escapeAsciidoc(short) + "\n\n")
+		}
+		if len(long) > 0 {
+			buf.WriteString("== Synopsis\n\n")
+			buf.WriteString(escapeAsciidoc(long) + "\n\n")
+		}
+		if cmd.Runnable() {
+			buf.WriteString(asciidocLiteral(cmd.UseLine()) + "\n")
+		}
+	}
+
+	if example := cmd.DisplayExample(); len(example) > 0 {
+		if header != nil {
+			buf.WriteString("== EXAMPLES\n\n")
+		} else {
+			buf.WriteString("== Examples\n\n")
+		}
+		buf.WriteString(asciidocLiteral(example) + "\n")
+	}
+
+	if !isTopic {
+		var show FlagMetadata
+		if header != nil {
+			show = header.FlagMetadata
+		}
+		flagSets := []struct {
+			title, manTitle string
+			flags           *pflag.FlagSet
+		}{
+			{"Options", "OPTIONS", docFlags(cmd, cmd.NonInheritedFlags(), show)},
+			{"Options inherited from parent commands", "OPTIONS INHERITED FROM PARENT COMMANDS", docFlags(cmd, cmd.InheritedFlags(), show)},
+		}
+		for _, set := range flagSets {
+			if !set.flags.HasAvailableFlags() {
+				continue
+			}
+			if header != nil {
+				buf.WriteString("== " + set.manTitle + "\n\n")
+				printAsciidocFlagList(buf, set.flags)
+			} else {
+				buf.WriteString("== " + set.title + "\n\n")
+				printAsciidocFlagTable(buf, set.flags)
+			}
+		}
+	}
+
+	if hasSeeAlso(cmd) {
+		var seeAlso []*cobra.Command
+		if cmd.HasParent() {
+			seeAlso = append(seeAlso, cmd.Parent())
+		}
+		children := cmd.Commands()
+		sort.Sort(byName(children))
+		for _, child := range children {
+			if isDocumented(child) {
+				seeAlso = append(seeAlso, child)
+			}
+		}
+
+		if header != nil {
+			buf.WriteString("== SEE ALSO\n\n")
+			refs := make([]string, 0, len(seeAlso))
+			for _, c := range seeAlso {
+				refs = append(refs, fmt.Sprintf("*%s*(%s)", strings.ReplaceAll(c.CommandPath(), " ", "-"), header.Section))
+			}
+			buf.WriteString(strings.Join(refs, ", ") + "\n\n")
+		} else {
+			buf.WriteString("== See also\n\n")
+			for _, c := range seeAlso {
+				path := c.CommandPath()
+				buf.WriteString(fmt.Sprintf("* %s - %s\n", linkHandler(path, strings.ReplaceAll(path, " ", "_")), escapeAsciidoc(c.DisplayShort())))
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	if !autoGenTagDisabled(cmd) {
+		if header != nil {
+			buf.WriteString("== HISTORY\n\n" + header.Date.Format("2-Jan-2006") + " Auto generated by spf13/cobra\n")
+		} else {
+			tag, err := autoGenTag()
+			if err != nil {
+				return err
+			}
+			buf.WriteString("_" + tag + "_\n")
+		}
+	}
+	_, err := io.WriteString(w, cobra.StripANSI(buf.String()))
+	return err
+}
+
+// asciidocLiteral returns s in a literal block, delimited by lines of four dots,
+// or more if s has a line of four dots which would end the block.
+func asciidocLiteral(s string) string {
+	delimiter := "...."
+	lines := strings.Split(s, "\n")
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t") == delimiter {
+			delimiter += "."
+			i = -1
+		}
+	}
+	return delimiter + "\n" + s + "\n" + delimiter + "\n"
+}
+
+// printAsciidocFlagTable prints the flags as a table with an anchor for each flag, e.g. "flag-output".
+func printAsciidocFlagTable(buf *bytes.Buffer, flags *pflag.FlagSet) {
+	buf.WriteString("[cols=\"2,1,1,4\",options=\"header\"]\n|===\n|Flag |Type |Default |Description\n")
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || len(flag.Deprecated) > 0 {
+			return
+		}
+		varname, usage := pflag.UnquoteUsage(flag)
+		names := "`--" + flag.Name + "`"
+		if len(flag.Shorthand) > 0 && len(flag.ShorthandDeprecated) == 0 {
+			names = "`-" + flag.Shorthand + "`, " + names
+		}
+		buf.WriteString(fmt.Sprintf("\n|[[flag-%s]]%s\n|%s\n|%s\n|%s\n",
+			flag.Name, names, escapeAsciidoc(varname), escapeAsciidoc(flagDefValue(flag)), escapeAsciidoc(usage)))
+	})
+	buf.WriteString("|===\n\n")
+}
+
+// printAsciidocFlagList prints the flags as a description list, as usual in man pages.
+func printAsciidocFlagList(buf *bytes.Buffer, flags *pflag.FlagSet) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || len(flag.Deprecated) > 0 {
+			return
+		}
+		varname, usage := pflag.UnquoteUsage(flag)
+		term := "*--" + flag.Name + "*"
+		if len(flag.Shorthand) > 0 && len(flag.ShorthandDeprecated) == 0 {
+			term = "*-" + flag.Shorthand + "*, " + term
+		}
+		if varname != "" {
+			term += "=_" + escapeAsciidoc(varname) + "_"
+		}
+		buf.WriteString(term + "::\n" + escapeAsciidoc(usage) + "\n")
+		if defValue := flagDefValue(flag); defValue != "" {
+			buf.WriteString("+\nDefault: " + escapeAsciidoc(defValue) + "\n")
+		}
+		buf.WriteString("\n")
+	})
+}
+
+// GenAsciidocTree will generate an AsciiDoc page for this command and all
+// descendants in the directory given.
+func GenAsciidocTree(cmd *cobra.Command, dir string) error {
+	emptyStr := func(s string) string { return "" }
+	return GenAsciidocTreeCustom(cmd, dir, emptyStr, defaultAsciidocLinkHandler)
+}
+
+// GenAsciidocTreeCustom is the same as GenAsciidocTree, but
+// with custom filePrepender and linkHandler.
+func GenAsciidocTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string) string, linkHandler func(string, string) string) error {
+	for _, c := range cmd.Commands() {
+		if !isDocumented(c) {
+			continue
+		}
+		if err := GenAsciidocTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
+			return err
+		}
+	}
+
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_") + asciidocExtension
+	filename := filepath.Join(dir, basename)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
+		return err
+	}
+	return GenAsciidocCustom(cmd, f, linkHandler)
+}
+
+// GenAsciidocManTree will generate an AsciiDoc page with the manpage doctype for this
+// command and all descendants in the directory given, e.g. "app-get.1.adoc".
+// The header may be nil.
+func GenAsciidocManTree(cmd *cobra.Command, header *GenManHeader, dir string) error {
+	if header == nil {
+		header = &GenManHeader{}
+	}
+	for _, c := range cmd.Commands() {
+		if !isDocumented(c) {
+			continue
+		}
+		if err := GenAsciidocManTree(c, header, dir); err != nil {
+			return err
+		}
+	}
+	section := "1"
+	if header.Section != "" {
+		section = header.Section
+	}
+
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "-") + "." + section + asciidocExtension
+	f, err := os.Create(filepath.Join(dir, basename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	headerCopy := *header
+	return GenAsciidocMan(cmd, &headerCopy, f)
+}
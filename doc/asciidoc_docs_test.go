@@ -0,0 +1,172 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGenAsciidocDoc(t *testing.T) {
+	// We generate on a subcommand so we have both subcommands and parents
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(echoCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "[[root_echo]]\n= root echo\n")
+	checkStringContains(t, output, echoCmd.Example)
+	checkStringContains(t, output, "|[[flag-boolone]]`-b`, `--boolone`")
+	checkStringContains(t, output, "== Options inherited from parent commands")
+	checkStringContains(t, output, "rootflag")
+	checkStringContains(t, output, "* xref:root.adoc[root] - "+rootCmd.Short)
+	checkStringContains(t, output, "* xref:root_echo_echosub.adoc[root echo echosub] - "+echoSubCmd.Short)
+	checkStringOmits(t, output, deprecatedCmd.Short)
+}
+
+func TestGenAsciidocCustomLinkHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	linkHandler := func(name, ref string) string {
+		return "xref:commands/" + ref + ".adoc[" + name + "]"
+	}
+	if err := GenAsciidocCustom(echoCmd, buf, linkHandler); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), "* xref:commands/root.adoc[root]")
+}
+
+func TestEscapeAsciidoc(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"*bold* and `code`", "{asterisk}bold{asterisk} and {backtick}code{backtick}"},
+		{"a|b [c]", "a{vbar}b {startsb}c{endsb}"},
+		{"snake_case {attr} <id>", "snake++_++case ++{++attr} ++<++id++>++"},
+		{"= not a title\n- not a list", "{empty}= not a title\n{empty}- not a list"},
+	}
+	for _, tc := range tests {
+		if got := escapeAsciidoc(tc.in); got != tc.want {
+			t.Errorf("escapeAsciidoc(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestGenAsciidocNoTag(t *testing.T) {
+	rootCmd.DisableAutoGenTag = true
+	defer func() { rootCmd.DisableAutoGenTag = false }()
+
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(rootCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringOmits(t, buf.String(), "Auto generated")
+}
+
+func TestGenAsciidocNoTagFromParent(t *testing.T) {
+	root := &cobra.Command{Use: "app", DisableAutoGenTag: true}
+	child := &cobra.Command{Use: "child", Run: emptyRun}
+	root.AddCommand(child)
+
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(child, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringOmits(t, buf.String(), "Auto generated")
+	if child.DisableAutoGenTag {
+		t.Error("Expected DisableAutoGenTag of the command to be left unchanged")
+	}
+}
+
+func TestGenAsciidocExampleWithDelimiter(t *testing.T) {
+	cmd := &cobra.Command{Use: "app", Example: "app print\n....\n.....", Run: emptyRun, DisableAutoGenTag: true}
+
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(cmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), "== Examples\n\n......\napp print\n....\n.....\n......\n")
+}
+
+func TestGenAsciidocMan(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	header := &GenManHeader{Section: "8", Date: &date, Manual: "Root Manual"}
+	buf := new(bytes.Buffer)
+	if err := GenAsciidocMan(echoCmd, header, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "= root-echo(8)\n:doctype: manpage\n:manmanual: Root Manual\n")
+	checkStringContains(t, output, ":revdate: Mar 2023\n")
+	checkStringContains(t, output, "== NAME\n\nroot-echo - ")
+	checkStringContains(t, output, "== SYNOPSIS\n\n*root echo ")
+	checkStringContains(t, output, "*-b*, *--boolone*::\n")
+	checkStringContains(t, output, "== SEE ALSO\n\n*root*(8), *root-echo-echosub*(8)")
+	checkStringContains(t, output, "== HISTORY\n\n1-Mar-2023 Auto generated by spf13/cobra")
+}
+
+func TestGenAsciidocTree(t *testing.T) {
+	c := &cobra.Command{Use: "do [OPTIONS] arg1 arg2"}
+	tmpdir, err := os.MkdirTemp("", "test-gen-asciidoc-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %s", err.Error())
+	}
+	defer os.RemoveAll(tmpdir)
+
+	prepender := func(filename string) string {
+		return ":page-source: " + filepath.Base(filename) + "\n"
+	}
+	if err := GenAsciidocTreeCustom(c, tmpdir, prepender, defaultAsciidocLinkHandler); err != nil {
+		t.Fatalf("GenAsciidocTree failed: %s", err.Error())
+	}
+	content, err := os.ReadFile(filepath.Join(tmpdir, "do.adoc"))
+	if err != nil {
+		t.Fatalf("Expected file 'do.adoc' to exist")
+	}
+	if !strings.HasPrefix(string(content), ":page-source: do.adoc\n") {
+		t.Errorf("Expected the file prepender output, got:\n%s", content)
+	}
+
+	if err := GenAsciidocManTree(c, nil, tmpdir); err != nil {
+		t.Fatalf("GenAsciidocManTree failed: %s", err.Error())
+	}
+	if _, err := os.Stat(filepath.Join(tmpdir, "do.1.adoc")); err != nil {
+		t.Fatalf("Expected file 'do.1.adoc' to exist")
+	}
+}
+
+func BenchmarkGenAsciidocToFile(b *testing.B) {
+	file, err := os.CreateTemp("", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := GenAsciidoc(rootCmd, file); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FlagMetadata selects the metadata of the flags shown in the generated docs. None
+// is shown by default; it is selected with the FlagMetadata field of GenManHeader,
+// GenMarkdownOptions, GenReSTOptions, TemplateOptions and GenYamlOptions. The man,
+// AsciiDoc man page, markdown, ReST and template generators append it to the usage
+// of the flags, e.g. "the token (required) (env: APP_TOKEN)"; the YAML generator
+// adds fields to the options.
+type FlagMetadata struct {
+	// Required shows the flags marked with MarkFlagRequired.
+	Required bool
+	// Files shows the file extensions of MarkFlagFilename and the flags marked with MarkFlagDirname.
+	Files bool
+	// Completions shows the values of MarkFlagValidValues and the flags with a completion function.
+	Completions bool
+	// EnvVars shows the environment variables of MarkFlagEnvVar.
+	EnvVars bool
+}
+
+// flagMetadata is the metadata of a flag selected by a FlagMetadata.
+type flagMetadata struct {
+	required bool
+	// files is set for flags marked with MarkFlagFilename, with extensions if any.
+	files      bool
+	extensions []string
+	dir        bool
+	values     []string
+	completion bool
+	envVar     string
+}
+
+// isRequiredFlag returns whether flag is marked with MarkFlagRequired.
+func isRequiredFlag(flag *pflag.Flag) bool {
+	required := flag.Annotations[cobra.BashCompOneRequiredFlag]
+	return len(required) > 0 && required[0] == "true"
+}
+
+func getFlagMetadata(cmd *cobra.Command, flag *pflag.Flag, show FlagMetadata) flagMetadata {
+	var m flagMetadata
+	m.required = show.Required && isRequiredFlag(flag)
+	if show.Files {
+		m.extensions, m.files = flag.Annotations[cobra.BashCompFilenameExt]
+		_, m.dir = flag.Annotations[cobra.BashCompSubdirsInDir]
+	}
+	if show.Completions {
+		m.values = flag.Annotations[cobra.FlagValidValuesAnnotation]
+		_, m.completion = cmd.GetFlagCompletionFunc(flag.Name)
+	}
+	if show.EnvVars {
+		m.envVar = cobra.FlagEnvVar(flag)
+	}
+	return m
+}
+
+// fileExtensions returns the file extensions as globs, e.g. "*.yaml".
+func (m flagMetadata) fileExtensions() []string {
+	globs := make([]string, 0, len(m.extensions))
+	for _, ext := range m.extensions {
+		globs = append(globs, "*."+strings.TrimPrefix(ext, "."))
+	}
+	return globs
+}
+
+// String formats the metadata to be appended to the usage of the flag.
+func (m flagMetadata) String() string {
+	var parts []string
+	if m.required {
+		parts = append(parts, "(required)")
+	}
+	if len(m.values) > 0 {
+		parts = append(parts, "(values: "+strings.Join(m.values, ", ")+")")
+	}
+	if m.completion {
+		parts = append(parts, "(completion available)")
+	}
+	switch {
+	case len(m.extensions) > 0:
+		parts = append(parts, "(files: "+strings.Join(m.fileExtensions(), ", ")+")")
+	case m.files:
+		parts = append(parts, "(file)")
+	}
+	if m.dir {
+		parts = append(parts, "(directory)")
+	}
+	if m.envVar != "" {
+		parts = append(parts, "(env: "+m.envVar+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// docFlags returns the flags as displayed, with the selected metadata appended to their usage.
+func docFlags(cmd *cobra.Command, flags *pflag.FlagSet, show FlagMetadata) *pflag.FlagSet {
+	localized := cmd.DisplayFlags(flags)
+	if show == (FlagMetadata{}) {
+		return localized
+	}
+	result := pflag.NewFlagSet(localized.Name(), pflag.ContinueOnError)
+	result.SortFlags = localized.SortFlags
+	localized.VisitAll(func(flag *pflag.Flag) {
+		f := *flag
+		if metadata := getFlagMetadata(cmd, flag, show).String(); metadata != "" {
+			if f.Usage != "" {
+				f.Usage += " "
+			}
+			f.Usage += metadata
+		}
+		result.AddFlag(&f)
+	})
+	return result
+}
@@ -0,0 +1,141 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newFlagMetadataCmd(t *testing.T) *cobra.Command {
+	c := &cobra.Command{Use: "app", Run: emptyRun}
+	c.Flags().String("token", "", "the token")
+	c.Flags().String("config", "", "the config file")
+	c.Flags().String("output", "", "the output format")
+	c.Flags().String("dir", "", "the work directory")
+	c.Flags().String("profile", "", "the profile")
+	for _, err := range []error{
+		c.MarkFlagRequired("token"),
+		c.MarkFlagEnvVar("token", "APP_TOKEN"),
+		c.MarkFlagFilename("config", "yaml", "yml"),
+		c.MarkFlagValidValues("output", "json", "yaml"),
+		c.MarkFlagDirname("dir"),
+		c.RegisterFlagCompletionFunc("profile", cobra.NoFileCompletions),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+var allFlagMetadata = FlagMetadata{Required: true, Files: true, Completions: true, EnvVars: true}
+
+func TestGenDocsFlagMetadata(t *testing.T) {
+	expected := []string{
+		"the token (required) (env: APP_TOKEN)",
+		"the config file (files: *.yaml, *.yml)",
+		"the output format (values: json, yaml)",
+		"the work directory (directory)",
+		"the profile (completion available)",
+	}
+	generators := map[string]func(*cobra.Command, *bytes.Buffer) error{
+		"markdown": func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenTemplate(c, buf, TemplateOptions{Templates: MarkdownTemplates(), Extension: ".md", FlagMetadata: allFlagMetadata})
+		},
+		"rest": func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenTemplate(c, buf, TemplateOptions{Templates: ReSTTemplates(), Extension: ".rst", FlagMetadata: allFlagMetadata})
+		},
+		"markdown options": func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenMarkdownFromOpts(c, buf, GenMarkdownOptions{FlagMetadata: allFlagMetadata})
+		},
+		"rest options": func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenReSTFromOpts(c, buf, GenReSTOptions{FlagMetadata: allFlagMetadata})
+		},
+		"man": func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenMan(c, &GenManHeader{FlagMetadata: allFlagMetadata}, buf)
+		},
+	}
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := gen(newFlagMetadataCmd(t), buf); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range expected {
+				checkStringContains(t, buf.String(), s)
+			}
+		})
+	}
+}
+
+func TestGenDocsFlagMetadataNotShownByDefault(t *testing.T) {
+	generators := map[string]func(*cobra.Command, *bytes.Buffer) error{
+		"markdown": func(c *cobra.Command, buf *bytes.Buffer) error { return GenMarkdown(c, buf) },
+		"rest":     func(c *cobra.Command, buf *bytes.Buffer) error { return GenReST(c, buf) },
+		"man":      func(c *cobra.Command, buf *bytes.Buffer) error { return GenMan(c, nil, buf) },
+		"yaml":     func(c *cobra.Command, buf *bytes.Buffer) error { return GenYaml(c, buf) },
+		"asciidoc": func(c *cobra.Command, buf *bytes.Buffer) error { return GenAsciidoc(c, buf) },
+	}
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := gen(newFlagMetadataCmd(t), buf); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range []string{"required", "APP_TOKEN", "yml", "values", "(directory)", "directory:", "(completion", "completion:"} {
+				checkStringOmits(t, buf.String(), s)
+			}
+		})
+	}
+}
+
+func TestGenYamlFlagMetadata(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenYamlFromOpts(newFlagMetadataCmd(t), buf, GenYamlOptions{FlagMetadata: allFlagMetadata}); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "      usage: the token\n      required: true\n      env_var: APP_TOKEN\n")
+	checkStringContains(t, output, "      file: true\n      file_extensions:\n        - '*.yaml'\n        - '*.yml'\n")
+	checkStringContains(t, output, "      valid_values:\n        - json\n        - yaml\n")
+	checkStringContains(t, output, "      directory: true\n")
+	checkStringContains(t, output, "      completion: true\n")
+}
+
+func TestGenDocsFlagMetadataToggles(t *testing.T) {
+	buf := new(bytes.Buffer)
+	opts := TemplateOptions{Templates: MarkdownTemplates(), Extension: ".md", FlagMetadata: FlagMetadata{Required: true}}
+	if err := GenTemplate(newFlagMetadataCmd(t), buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "the token (required)\n")
+	checkStringOmits(t, output, "APP_TOKEN")
+	checkStringOmits(t, output, "*.yaml")
+	checkStringOmits(t, output, "values:")
+	checkStringOmits(t, output, "completion available")
+
+	buf.Reset()
+	if err := GenYamlFromOpts(newFlagMetadataCmd(t), buf, GenYamlOptions{FlagMetadata: FlagMetadata{EnvVars: true}}); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), "env_var: APP_TOKEN")
+	checkStringOmits(t, buf.String(), "required")
+}
@@ -0,0 +1,80 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/cobra"
+)
+
+func newHelpTopicsTestCommands(t *testing.T) *cobra.Command {
+	rootCmd := &cobra.Command{Use: "app", Run: emptyRun}
+	topics := fstest.MapFS{
+		"topics/environment.md": {Data: []byte("# Environment variables\n\n## Variables\n\nSet `APP_HOME` to change the home directory.\n")},
+	}
+	if err := rootCmd.AddHelpTopics(topics, "topics"); err != nil {
+		t.Fatal(err)
+	}
+	return rootCmd
+}
+
+func TestGenMarkdownTreeHelpTopics(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "test-gen-md-tree-topics")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	rootCmd := newHelpTopicsTestCommands(t)
+	if err := GenMarkdownTree(rootCmd, tmpdir); err != nil {
+		t.Fatalf("GenMarkdownTree failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpdir, "app_environment.md"))
+	if err != nil {
+		t.Fatalf("Expected file 'app_environment.md' to exist: %v", err)
+	}
+	checkStringContains(t, string(content), "## app environment\n\nEnvironment variables\n\n### Variables\n\nSet `APP_HOME` to change the home directory.\n")
+	checkStringOmits(t, string(content), "### Options")
+
+	root, err := os.ReadFile(filepath.Join(tmpdir, "app.md"))
+	if err != nil {
+		t.Fatalf("Expected file 'app.md' to exist: %v", err)
+	}
+	checkStringContains(t, string(root), "* [app environment](app_environment.md)\t - Environment variables\n")
+}
+
+func TestGenManHelpTopic(t *testing.T) {
+	rootCmd := newHelpTopicsTestCommands(t)
+	topic, _, err := rootCmd.Find([]string{"environment"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := GenMan(topic, &GenManHeader{Title: "Project", Section: "7"}, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	checkStringContains(t, output, ".SS Variables")
+	checkStringOmits(t, output, "SYNOPSIS")
+	checkStringOmits(t, output, "OPTIONS")
+}
@@ -0,0 +1,367 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	htmlExtension       = ".html"
+	htmlIndexFile       = "index.html"
+	htmlSearchIndexFile = "search-index.json"
+	// htmlSearchScriptFile wraps the search index in a script, as browsers don't
+	// let the pages fetch the JSON when they are opened as files.
+	htmlSearchScriptFile = "search-index.js"
+)
+
+// HTMLCommand describes a command for the templates of GenHTMLTreeCustom.
+type HTMLCommand struct {
+	Name           string
+	Path           string
+	Aliases        []string
+	Short          string
+	Long           string
+	UseLine        string
+	Example        string
+	Runnable       bool
+	Flags          []HTMLFlag
+	InheritedFlags []HTMLFlag
+	// File is the name of the page of the command, e.g. "app_get.html".
+	File string
+	// Parent is nil for the root command.
+	Parent   *HTMLCommand
+	Children []*HTMLCommand
+	// Command is the described command.
+	Command *cobra.Command
+}
+
+// HTMLFlag describes a flag for the templates of GenHTMLTreeCustom.
+type HTMLFlag struct {
+	Name      string
+	Shorthand string
+	// ValueName is the name of the value of the flag, e.g. "string", empty for boolean flags.
+	ValueName string
+	// Default is empty if the default value is the zero value of the flag.
+	Default string
+	Usage   string
+	Long    string
+	Example string
+	// Anchor is the id of the flag in the page of its command, e.g. "flag-output".
+	Anchor string
+}
+
+// HTMLPage is the data of the "page" and "index" templates of GenHTMLTreeCustom.
+type HTMLPage struct {
+	Title string
+	// Root is the root of the generated tree, for navigation.
+	Root *HTMLCommand
+	// Commands are all the commands of the generated tree, depth first.
+	Commands []*HTMLCommand
+	// Command is the command of the page, nil for the index.
+	Command *HTMLCommand
+	// AutoGenTag is empty if DisableAutoGenTag is set on the command of the page.
+	AutoGenTag string
+}
+
+// htmlSearchEntry is an entry of the search index.
+type htmlSearchEntry struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+// HTMLTemplates returns a new copy of the default templates of GenHTMLTree, which
+// can be modified and given to GenHTMLTreeCustom. The "page" template is executed
+// for each command and the "index" template for index.html, both with an HTMLPage.
+// They use the "header", "nav", "flags", "footer", "style" and "script" templates,
+// which can be redefined too, e.g.:
+//
+//	tmpl := doc.HTMLTemplates()
+//	template.Must(tmpl.Parse(`{{define "style"}}body { font-family: serif; }{{end}}`))
+//	err := doc.GenHTMLTreeCustom(rootCmd, "site", tmpl)
+//
+// The "paragraphs" function formats text, such as Long, as HTML paragraphs.
+func HTMLTemplates() *template.Template {
+	return template.Must(template.New("html").Funcs(template.FuncMap{
+		"paragraphs": htmlParagraphs,
+		"join":       strings.Join,
+	}).Parse(defaultHTMLTemplates))
+}
+
+// GenHTMLTree generates a static HTML site documenting cmd and all its descendants
+// in the directory dir: a page for each command, an index.html page, and a
+// search-index.json file indexing the commands and flags. The search box of the
+// pages loads the same index from search-index.js, so that it also works when the
+// pages are opened as files.
+func GenHTMLTree(cmd *cobra.Command, dir string) error {
+	return GenHTMLTreeCustom(cmd, dir, HTMLTemplates())
+}
+
+// GenHTMLTreeCustom is the same as GenHTMLTree, but with custom templates, see HTMLTemplates.
+func GenHTMLTreeCustom(cmd *cobra.Command, dir string, tmpl *template.Template) error {
+	root := newHTMLCommand(cmd, nil)
+	var commands []*HTMLCommand
+	var walk func(c *HTMLCommand)
+	walk = func(c *HTMLCommand) {
+		commands = append(commands, c)
+		for _, child := range c.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	tag, err := autoGenTag()
+	if err != nil {
+		return err
+	}
+	pageAutoGenTag := func(c *cobra.Command) string {
+		if autoGenTagDisabled(c) {
+			return ""
+		}
+		return tag
+	}
+
+	var searchIndex []htmlSearchEntry
+	for _, c := range commands {
+		page := &HTMLPage{Title: c.Path, Root: root, Commands: commands, Command: c, AutoGenTag: pageAutoGenTag(c.Command)}
+		if err := writeHTMLPage(filepath.Join(dir, c.File), tmpl, "page", page); err != nil {
+			return err
+		}
+		searchIndex = append(searchIndex, htmlSearchEntry{Title: c.Path, URL: c.File, Text: c.Short})
+		for _, flags := range [][]HTMLFlag{c.Flags, c.InheritedFlags} {
+			for _, f := range flags {
+				searchIndex = append(searchIndex, htmlSearchEntry{Title: c.Path + " --" + f.Name, URL: c.File + "#" + f.Anchor, Text: f.Usage})
+			}
+		}
+	}
+
+	index := &HTMLPage{Title: root.Path, Root: root, Commands: commands, AutoGenTag: pageAutoGenTag(cmd)}
+	if err := writeHTMLPage(filepath.Join(dir, htmlIndexFile), tmpl, "index", index); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(searchIndex, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeHTMLFile(filepath.Join(dir, htmlSearchIndexFile), string(data)+"\n"); err != nil {
+		return err
+	}
+	return writeHTMLFile(filepath.Join(dir, htmlSearchScriptFile), fmt.Sprintf("var searchIndex = %s;\n", data))
+}
+
+func writeHTMLFile(filename, content string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+func writeHTMLPage(filename string, tmpl *template.Template, name string, page *HTMLPage) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.ExecuteTemplate(f, name, page)
+}
+
+// newHTMLCommand describes cmd and its documented descendants.
+func newHTMLCommand(cmd *cobra.Command, parent *HTMLCommand) *HTMLCommand {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+
+	c := &HTMLCommand{
+		Name:     cmd.Name(),
+		Path:     cmd.CommandPath(),
+		Aliases:  cmd.Aliases,
+		Short:    cobra.StripANSI(cmd.DisplayShort()),
+		Long:     cobra.StripANSI(cmd.LocalizedLong()),
+		Example:  cobra.StripANSI(cmd.DisplayExample()),
+		Runnable: cmd.Runnable(),
+		File:     strings.ReplaceAll(cmd.CommandPath(), " ", "_") + htmlExtension,
+		Parent:   parent,
+		Command:  cmd,
+	}
+	if c.Runnable {
+		c.UseLine = cmd.UseLine()
+	}
+	if _, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]; !isTopic {
+		c.Flags = htmlFlags(cmd, cmd.NonInheritedFlags())
+		c.InheritedFlags = htmlFlags(cmd, cmd.InheritedFlags())
+	}
+
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+	for _, child := range children {
+		if isDocumented(child) {
+			c.Children = append(c.Children, newHTMLCommand(child, c))
+		}
+	}
+	return c
+}
+
+func htmlFlags(cmd *cobra.Command, flags *pflag.FlagSet) []HTMLFlag {
+	var result []HTMLFlag
+	cmd.DisplayFlags(flags).VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || len(flag.Deprecated) > 0 {
+			return
+		}
+		valueName, usage := pflag.UnquoteUsage(flag)
+		f := HTMLFlag{
+			Name:      flag.Name,
+			ValueName: valueName,
+			Usage:     cobra.StripANSI(usage),
+			Long:      cmd.LocalizedFlagLong(flag),
+			Example:   cmd.LocalizedFlagExample(flag),
+			Anchor:    "flag-" + flag.Name,
+		}
+		if len(flag.ShorthandDeprecated) == 0 {
+			f.Shorthand = flag.Shorthand
+		}
+		f.Default = flagDefValue(flag)
+		result = append(result, f)
+	})
+	return result
+}
+
+// htmlParagraphs formats text as HTML paragraphs. Blocks of indented lines
+// are kept as preformatted text.
+func htmlParagraphs(text string) template.HTML {
+	var sb strings.Builder
+	for _, block := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		block = strings.Trim(block, "\n")
+		if block == "" {
+			continue
+		}
+		if strings.HasPrefix(block, " ") || strings.HasPrefix(block, "\t") {
+			sb.WriteString("<pre>" + template.HTMLEscapeString(block) + "</pre>\n")
+		} else {
+			sb.WriteString("<p>" + template.HTMLEscapeString(block) + "</p>\n")
+		}
+	}
+	return template.HTML(sb.String()) //nolint:gosec // the text is escaped
+}
+
+const defaultHTMLTemplates = `{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>{{template "style"}}</style>
+</head>
+<body>
+<nav>
+<input id="search" type="search" placeholder="Search" autocomplete="off">
+<ul id="search-results"></ul>
+<ul class="tree">{{template "nav" .Root}}</ul>
+</nav>
+<main>
+{{end}}
+
+{{define "nav"}}<li><a href="{{.File}}">{{.Name}}</a>{{with .Children}}<ul>{{range .}}{{template "nav" .}}{{end}}</ul>{{end}}</li>{{end}}
+
+{{define "flags"}}<dl class="flags">
+{{range .}}<dt id="{{.Anchor}}"><a href="#{{.Anchor}}"><code>{{if .Shorthand}}-{{.Shorthand}}, {{end}}--{{.Name}}{{with .ValueName}} {{.}}{{end}}</code></a></dt>
+<dd>{{.Usage}}{{if and .Usage .Default}} {{end}}{{with .Default}}(default {{.}}){{end}}{{with .Long}}
+{{paragraphs .}}{{end}}{{with .Example}}
+<pre>{{.}}</pre>{{end}}</dd>
+{{end}}</dl>
+{{end}}
+
+{{define "footer"}}</main>
+{{with .AutoGenTag}}<footer>{{.}}</footer>
+{{end}}<script src="search-index.js"></script>
+<script>{{template "script"}}</script>
+</body>
+</html>
+{{end}}
+
+{{define "page"}}{{template "header" .}}{{with .Command}}<h1>{{.Path}}</h1>
+<p class="short">{{.Short}}</p>
+{{with .Long}}<h2 id="synopsis">Synopsis</h2>
+{{paragraphs .}}{{end}}{{if .Runnable}}<pre class="usage">{{.UseLine}}</pre>
+{{end}}{{with .Aliases}}<p class="aliases">Aliases: {{join . ", "}}</p>
+{{end}}{{with .Example}}<h2 id="examples">Examples</h2>
+<pre>{{.}}</pre>
+{{end}}{{with .Flags}}<h2 id="options">Options</h2>
+{{template "flags" .}}{{end}}{{with .InheritedFlags}}<h2 id="inherited-options">Options inherited from parent commands</h2>
+{{template "flags" .}}{{end}}{{if or .Parent .Children}}<h2 id="see-also">See also</h2>
+<ul class="see-also">
+{{with .Parent}}<li><a href="{{.File}}">{{.Path}}</a> - {{.Short}}</li>
+{{end}}{{range .Children}}<li><a href="{{.File}}">{{.Path}}</a> - {{.Short}}</li>
+{{end}}</ul>
+{{end}}{{end}}{{template "footer" .}}{{end}}
+
+{{define "index"}}{{template "header" .}}<h1>{{.Root.Path}}</h1>
+<p class="short">{{.Root.Short}}</p>
+<h2 id="commands">Commands</h2>
+<table class="commands">
+{{range .Commands}}<tr><td><a href="{{.File}}">{{.Path}}</a></td><td>{{.Short}}</td></tr>
+{{end}}</table>
+{{template "footer" .}}{{end}}
+
+{{define "style"}}
+body { display: flex; margin: 0; font-family: sans-serif; line-height: 1.5; }
+nav { width: 16rem; padding: 1rem; border-right: 1px solid #ddd; }
+nav ul { list-style: none; padding-left: 1rem; }
+nav > ul { padding-left: 0; }
+main { flex: 1; max-width: 50rem; padding: 1rem 2rem; }
+pre { background: #f6f8fa; padding: 0.5rem; overflow-x: auto; }
+dt { font-weight: bold; }
+dd { margin-bottom: 1rem; }
+footer { position: fixed; bottom: 0; right: 0; padding: 0.5rem; font-size: small; color: #666; }
+{{end}}
+
+{{define "script"}}
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  var index = typeof searchIndex === "undefined" ? [] : searchIndex;
+  function search() {
+    var term = input.value.toLowerCase().trim();
+    results.innerHTML = "";
+    if (!term) {
+      return;
+    }
+    index.filter(function (entry) {
+      return entry.title.toLowerCase().indexOf(term) >= 0 || entry.text.toLowerCase().indexOf(term) >= 0;
+    }).slice(0, 20).forEach(function (entry) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.title;
+      item.appendChild(link);
+      results.appendChild(item);
+    });
+  }
+  input.addEventListener("input", search);
+})();
+{{end}}`
@@ -0,0 +1,133 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"encoding/json"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenHTMLTree(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "test-gen-html-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := GenHTMLTree(rootCmd, tmpdir); err != nil {
+		t.Fatalf("GenHTMLTree failed: %v", err)
+	}
+
+	for _, name := range []string{"index.html", "root.html", "root_echo.html", "root_echo_times.html", "root_echo_echosub.html"} {
+		if _, err := os.Stat(filepath.Join(tmpdir, name)); err != nil {
+			t.Errorf("Expected file %q to exist: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpdir, "root_echo_deprecated.html")); err == nil {
+		t.Error("Expected no page for a deprecated command")
+	}
+
+	page, err := os.ReadFile(filepath.Join(tmpdir, "root_echo.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(page)
+	checkStringContains(t, output, "<h1>root echo</h1>")
+	checkStringContains(t, output, `<dt id="flag-intone"><a href="#flag-intone"><code>-i, --intone int</code></a></dt>`)
+	checkStringContains(t, output, "<dd>help message for flag intone (default 123)</dd>")
+	checkStringContains(t, output, `<dt id="flag-strtwo">`)
+	checkStringContains(t, output, `<li><a href="root.html">root</a> - Root short description</li>`)
+	checkStringContains(t, output, `<li><a href="root_echo_times.html">root echo times</a> - Echo anything to the screen more times</li>`)
+	checkStringContains(t, output, `<ul class="tree"><li><a href="root.html">root</a><ul>`)
+
+	index, err := os.ReadFile(filepath.Join(tmpdir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, string(index), `<tr><td><a href="root_echo_times.html">root echo times</a></td>`)
+
+	checkStringContains(t, output, `<script src="search-index.js"></script>`)
+	data, err := os.ReadFile(filepath.Join(tmpdir, "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []htmlSearchEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("Invalid search index: %v", err)
+	}
+	script, err := os.ReadFile(filepath.Join(tmpdir, "search-index.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "var searchIndex = " + strings.TrimSuffix(string(data), "\n") + ";\n"; string(script) != expected {
+		t.Errorf("Expected the search script to assign the JSON index, got %q", script)
+	}
+	found := map[string]string{}
+	for _, entry := range entries {
+		found[entry.Title] = entry.URL
+	}
+	if url := found["root echo"]; url != "root_echo.html" {
+		t.Errorf("Expected root echo in the search index, got %q", url)
+	}
+	if url := found["root echo --intone"]; url != "root_echo.html#flag-intone" {
+		t.Errorf("Expected the flag intone in the search index, got %q", url)
+	}
+}
+
+func TestGenHTMLTreeCustomTemplates(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "test-gen-html-tree-custom")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	tmpl := HTMLTemplates()
+	template.Must(tmpl.Parse(`{{define "footer"}}<footer>custom footer</footer>{{end}}`))
+	if err := GenHTMLTreeCustom(rootCmd, tmpdir, tmpl); err != nil {
+		t.Fatalf("GenHTMLTreeCustom failed: %v", err)
+	}
+	page, err := os.ReadFile(filepath.Join(tmpdir, "root_echo_echosub.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, string(page), "<footer>custom footer</footer>")
+
+	// The default templates are not modified
+	page2, err := os.ReadFile(filepath.Join(tmpdir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, string(page2), "custom footer")
+	if err := GenHTMLTree(rootCmd, tmpdir); err != nil {
+		t.Fatalf("GenHTMLTree failed: %v", err)
+	}
+	page, err = os.ReadFile(filepath.Join(tmpdir, "root_echo_echosub.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringOmits(t, string(page), "custom footer")
+}
+
+func TestHTMLParagraphs(t *testing.T) {
+	got := htmlParagraphs("First <paragraph>.\n\n  indented\n  code\n\nLast.")
+	expected := template.HTML("<p>First &lt;paragraph&gt;.</p>\n<pre>  indented\n  code</pre>\n<p>Last.</p>\n")
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
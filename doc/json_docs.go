@@ -0,0 +1,186 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// JSONSchemaVersion is the version of the schema of JSONDoc. It is incremented
+// whenever a change could break the readers of the documents.
+const JSONSchemaVersion = 1
+
+// JSONDoc is the document written by GenJSON, describing a whole command tree.
+type JSONDoc struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Root          *JSONCommand `json:"root"`
+}
+
+// JSONCommand describes a command and its subcommands.
+type JSONCommand struct {
+	Name        string                `json:"name"`
+	Path        string                `json:"path"`
+	UseLine     string                `json:"useLine"`
+	Aliases     []string              `json:"aliases,omitempty"`
+	Short       string                `json:"short,omitempty"`
+	Long        string                `json:"long,omitempty"`
+	Example     string                `json:"example,omitempty"`
+	Runnable    bool                  `json:"runnable"`
+	Hidden      bool                  `json:"hidden,omitempty"`
+	Deprecated  string                `json:"deprecated,omitempty"`
+	GroupID     string                `json:"groupId,omitempty"`
+	Annotations map[string]string     `json:"annotations,omitempty"`
+	Args        *JSONArgs             `json:"args,omitempty"`
+	Flags       []JSONFlag            `json:"flags,omitempty"`
+	FlagGroups  []cobra.FlagGroupInfo `json:"flagGroups,omitempty"`
+	Groups      []cobra.GroupInfo     `json:"groups,omitempty"`
+	Commands    []*JSONCommand        `json:"commands,omitempty"`
+}
+
+// JSONArgs describes the positional arguments of a command.
+type JSONArgs struct {
+	ValidArgs  []string       `json:"validArgs,omitempty"`
+	ArgAliases []string       `json:"argAliases,omitempty"`
+	Arguments  []JSONArgument `json:"arguments,omitempty"`
+}
+
+// JSONArgument describes a positional argument, see cobra.Argument.
+type JSONArgument struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// JSONFlag describes a flag of a command.
+type JSONFlag struct {
+	Name         string `json:"name"`
+	Shorthand    string `json:"shorthand,omitempty"`
+	Type         string `json:"type"`
+	Default      string `json:"default"`
+	NoOptDefault string `json:"noOptDefault,omitempty"`
+	Usage        string `json:"usage"`
+	Required     bool   `json:"required,omitempty"`
+	Hidden       bool   `json:"hidden,omitempty"`
+	Deprecated   string `json:"deprecated,omitempty"`
+	// Persistent is true for the persistent flags defined by the command.
+	Persistent bool `json:"persistent,omitempty"`
+	// InheritedFrom is the path of the command defining the flag, for inherited flags.
+	InheritedFrom string `json:"inheritedFrom,omitempty"`
+	// Annotations are the annotations of the flag, such as the completion annotations
+	// set with MarkFlagFilename or MarkFlagValidValues.
+	Annotations map[string][]string `json:"annotations,omitempty"`
+}
+
+// GenJSONTree writes the JSON document describing cmd and all its descendants,
+// see GenJSON, to the file named after cmd in the directory dir, e.g. "app.json".
+func GenJSONTree(cmd *cobra.Command, dir string) error {
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".json"
+	f, err := os.Create(filepath.Join(dir, basename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return GenJSON(cmd, f)
+}
+
+// GenJSON writes a single JSON document describing cmd and all its descendants,
+// following the schema of version JSONSchemaVersion, for other tooling.
+// Unlike the other generators, it includes the hidden and deprecated commands and
+// flags, with their status.
+func GenJSON(cmd *cobra.Command, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&JSONDoc{SchemaVersion: JSONSchemaVersion, Root: newJSONCommand(cmd)})
+}
+
+func newJSONCommand(cmd *cobra.Command) *JSONCommand {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+
+	info := cmd.HelpInfo()
+	c := &JSONCommand{
+		Name:        info.Name,
+		Path:        info.Path,
+		UseLine:     info.UseLine,
+		Aliases:     info.Aliases,
+		Short:       cobra.StripANSI(info.Short),
+		Long:        cobra.StripANSI(info.Long),
+		Example:     cobra.StripANSI(info.Example),
+		Runnable:    info.Runnable,
+		Hidden:      info.Hidden,
+		Deprecated:  info.Deprecated,
+		GroupID:     cmd.GroupID,
+		Annotations: cmd.Annotations,
+		FlagGroups:  info.FlagGroups,
+		Groups:      info.Groups,
+	}
+	if len(cmd.ValidArgs) > 0 || len(cmd.ArgAliases) > 0 || len(cmd.Arguments) > 0 {
+		c.Args = &JSONArgs{ValidArgs: cmd.ValidArgs, ArgAliases: cmd.ArgAliases}
+		for _, arg := range cmd.Arguments {
+			c.Args.Arguments = append(c.Args.Arguments, JSONArgument{Name: arg.Name, Optional: arg.Optional, Variadic: arg.Variadic})
+		}
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.LocalizedFlags(cmd.NonInheritedFlags()).VisitAll(func(flag *pflag.Flag) {
+		f := newJSONFlag(flag)
+		f.Persistent = persistent.Lookup(flag.Name) != nil
+		c.Flags = append(c.Flags, f)
+	})
+	cmd.LocalizedFlags(cmd.InheritedFlags()).VisitAll(func(flag *pflag.Flag) {
+		f := newJSONFlag(flag)
+		cmd.VisitParents(func(parent *cobra.Command) {
+			if f.InheritedFrom == "" && parent.PersistentFlags().Lookup(flag.Name) != nil {
+				f.InheritedFrom = parent.CommandPath()
+			}
+		})
+		c.Flags = append(c.Flags, f)
+	})
+
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+	for _, child := range children {
+		c.Commands = append(c.Commands, newJSONCommand(child))
+	}
+	return c
+}
+
+func newJSONFlag(flag *pflag.Flag) JSONFlag {
+	f := JSONFlag{
+		Name:        flag.Name,
+		Shorthand:   flag.Shorthand,
+		Type:        flag.Value.Type(),
+		Default:     flag.DefValue,
+		Usage:       cobra.StripANSI(flag.Usage),
+		Hidden:      flag.Hidden,
+		Deprecated:  flag.Deprecated,
+		Annotations: flag.Annotations,
+	}
+	if flag.NoOptDefVal != "" && flag.Value.Type() != "bool" {
+		f.NoOptDefault = flag.NoOptDefVal
+	}
+	if required := flag.Annotations[cobra.BashCompOneRequiredFlag]; len(required) > 0 && required[0] == "true" {
+		f.Required = true
+	}
+	return f
+}
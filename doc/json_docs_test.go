@@ -0,0 +1,138 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newJSONTestCommands() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "app", Short: "the app"}
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file")
+	_ = rootCmd.MarkPersistentFlagFilename("config", "yaml")
+	rootCmd.AddGroup(&cobra.Group{ID: "manage", Title: "Management Commands:"})
+
+	getCmd := &cobra.Command{
+		Use:         "get TYPE",
+		Aliases:     []string{"g"},
+		Short:       "get resources",
+		GroupID:     "manage",
+		ValidArgs:   []string{"pods", "nodes"},
+		Arguments:   []cobra.Argument{{Name: "TYPE"}},
+		Annotations: map[string]string{"category": "read"},
+		Run:         emptyRun,
+	}
+	getCmd.Flags().Bool("json", false, "JSON output")
+	getCmd.Flags().Bool("yaml", false, "YAML output")
+	getCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	getCmd.Flags().Int("limit", 10, "maximum number of results")
+	_ = getCmd.MarkFlagRequired("limit")
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(&cobra.Command{Use: "secret", Hidden: true, Run: emptyRun})
+	return rootCmd
+}
+
+func TestGenJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenJSON(newJSONTestCommands(), buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc JSONDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if doc.SchemaVersion != JSONSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", JSONSchemaVersion, doc.SchemaVersion)
+	}
+	root := doc.Root
+	if root.Path != "app" || len(root.Groups) != 1 || root.Groups[0].ID != "manage" {
+		t.Errorf("Unexpected root command: %+v", root)
+	}
+	config := findJSONFlag(t, root.Flags, "config")
+	if !config.Persistent || config.InheritedFrom != "" || config.Type != "string" || config.Shorthand != "c" {
+		t.Errorf("Unexpected config flag: %+v", config)
+	}
+	if ext := config.Annotations[cobra.BashCompFilenameExt]; len(ext) != 1 || ext[0] != "yaml" {
+		t.Errorf("Expected the completion annotation of the config flag, got %v", config.Annotations)
+	}
+
+	var get, secret *JSONCommand
+	for _, c := range root.Commands {
+		switch c.Name {
+		case "get":
+			get = c
+		case "secret":
+			secret = c
+		}
+	}
+	if get == nil || secret == nil {
+		t.Fatalf("Expected the get and secret commands, got %+v", root.Commands)
+	}
+	if !secret.Hidden {
+		t.Error("Expected the secret command to be hidden")
+	}
+	if get.Path != "app get" || get.GroupID != "manage" || len(get.Aliases) != 1 || get.Annotations["category"] != "read" {
+		t.Errorf("Unexpected get command: %+v", get)
+	}
+	if get.Args == nil || len(get.Args.ValidArgs) != 2 || len(get.Args.Arguments) != 1 || get.Args.Arguments[0].Name != "TYPE" {
+		t.Errorf("Unexpected args: %+v", get.Args)
+	}
+	if len(get.FlagGroups) != 1 || get.FlagGroups[0].Kind != cobra.FlagGroupMutuallyExclusive {
+		t.Errorf("Unexpected flag groups: %+v", get.FlagGroups)
+	}
+	limit := findJSONFlag(t, get.Flags, "limit")
+	if !limit.Required || limit.Type != "int" || limit.Default != "10" {
+		t.Errorf("Unexpected limit flag: %+v", limit)
+	}
+	if inherited := findJSONFlag(t, get.Flags, "config"); inherited.InheritedFrom != "app" || inherited.Persistent {
+		t.Errorf("Unexpected inherited config flag: %+v", inherited)
+	}
+}
+
+func TestGenJSONTree(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "test-gen-json-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := GenJSONTree(newJSONTestCommands(), tmpdir); err != nil {
+		t.Fatalf("GenJSONTree failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpdir, "app.json"))
+	if err != nil {
+		t.Fatalf("Expected file 'app.json' to exist: %v", err)
+	}
+	checkStringContains(t, string(content), `"schemaVersion": 1`)
+	checkStringContains(t, string(content), `"path": "app get"`)
+}
+
+func findJSONFlag(t *testing.T, flags []JSONFlag, name string) JSONFlag {
+	t.Helper()
+	for _, f := range flags {
+		if f.Name == name {
+			return f
+		}
+	}
+	t.Fatalf("Flag %q not found in %+v", name, flags)
+	return JSONFlag{}
+}
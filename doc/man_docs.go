@@ -21,7 +21,6 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -51,7 +50,7 @@ func GenManTreeFromOpts(cmd *cobra.Command, opts GenManTreeOptions) error {
 		header = &GenManHeader{}
 	}
 	for _, c := range cmd.Commands() {
-		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+		if !isDocumented(c) {
 			continue
 		}
 		if err := GenManTreeFromOpts(c, opts); err != nil {
@@ -91,6 +90,11 @@ type GenManTreeOptions struct {
 // include the title, section, date, source, and manual. We will use the
 // current time if Date is unset and will use "Auto generated by spf13/cobra"
 // if the Source is unset.
+//
+// The other fields are the markdown text of optional sections, written in all
+// the pages, unless a command has the corresponding annotation, e.g.
+// ManExitStatusAnnotation, whose value is used instead. Environment adds
+// environment variables, by name, to the ENVIRONMENT section.
 type GenManHeader struct {
 	Title   string
 	Section string
@@ -98,8 +102,29 @@ type GenManHeader struct {
 	date    string
 	Source  string
 	Manual  string
+
+	Environment map[string]string
+	ExitStatus  string
+	Files       string
+	Authors     string
+	Bugs        string
+
+	// FlagMetadata selects the metadata of the flags shown in the options, and
+	// whether the environment variables of the flags are listed in ENVIRONMENT.
+	FlagMetadata FlagMetadata
 }
 
+// Annotations of a command setting the markdown text of sections of its man
+// page, instead of the corresponding field of GenManHeader. The text of
+// ManEnvironmentAnnotation follows the environment variables of the page.
+const (
+	ManEnvironmentAnnotation = "cobra_annotation_man_environment"
+	ManExitStatusAnnotation  = "cobra_annotation_man_exit_status"
+	ManFilesAnnotation       = "cobra_annotation_man_files"
+	ManAuthorsAnnotation     = "cobra_annotation_man_authors"
+	ManBugsAnnotation        = "cobra_annotation_man_bugs"
+)
+
 // GenMan will generate a man page for the given command and write it to
 // w. The header argument may be nil, however obviously w may not.
 func GenMan(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
@@ -111,7 +136,7 @@ func GenMan(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
 	}
 
 	b := genMan(cmd, header)
-	_, err := w.Write(md2man.Render(b))
+	_, err := w.Write(md2man.Render([]byte(cobra.StripANSI(string(b)))))
 	return err
 }
 
@@ -123,13 +148,9 @@ func fillHeader(header *GenManHeader, name string, disableAutoGen bool) error {
 		header.Section = "1"
 	}
 	if header.Date == nil {
-		now := time.Now()
-		if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
-			unixEpoch, err := strconv.ParseInt(epoch, 10, 64)
-			if err != nil {
-				return fmt.Errorf("invalid SOURCE_DATE_EPOCH: %v", err)
-			}
-			now = time.Unix(unixEpoch, 0)
+		now, err := generationDate()
+		if err != nil {
+			return err
 		}
 		header.Date = &now
 	}
@@ -141,17 +162,23 @@ func fillHeader(header *GenManHeader, name string, disableAutoGen bool) error {
 }
 
 func manPreamble(buf io.StringWriter, header *GenManHeader, cmd *cobra.Command, dashedName string) {
-	description := cmd.Long
+	description := cmd.LocalizedLong()
 	if len(description) == 0 {
-		description = cmd.Short
+		description = cmd.DisplayShort()
+	}
+	topic, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]
+	if isTopic {
+		description = shiftMarkdownHeadings(topic, 1)
 	}
 
 	cobra.WriteStringAndCheck(buf, fmt.Sprintf(`%% "%s" "%s" "%s" "%s" "%s"
 # NAME
 `, header.Title, header.Section, header.date, header.Source, header.Manual))
-	cobra.WriteStringAndCheck(buf, fmt.Sprintf("%s \\- %s\n\n", dashedName, cmd.Short))
-	cobra.WriteStringAndCheck(buf, "# SYNOPSIS\n")
-	cobra.WriteStringAndCheck(buf, fmt.Sprintf("**%s**\n\n", cmd.UseLine()))
+	cobra.WriteStringAndCheck(buf, fmt.Sprintf("%s \\- %s\n\n", dashedName, cmd.DisplayShort()))
+	if !isTopic {
+		cobra.WriteStringAndCheck(buf, "# SYNOPSIS\n")
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf("**%s**\n\n", cmd.UseLine()))
+	}
 	cobra.WriteStringAndCheck(buf, "# DESCRIPTION\n")
 	cobra.WriteStringAndCheck(buf, description+"\n\n")
 }
@@ -184,19 +211,96 @@ func manPrintFlags(buf io.StringWriter, flags *pflag.FlagSet) {
 	})
 }
 
-func manPrintOptions(buf io.StringWriter, command *cobra.Command) {
-	flags := command.NonInheritedFlags()
+func manPrintOptions(buf io.StringWriter, command *cobra.Command, header *GenManHeader) {
+	flags := docFlags(command, command.NonInheritedFlags(), header.FlagMetadata)
 	if flags.HasAvailableFlags() {
 		cobra.WriteStringAndCheck(buf, "# OPTIONS\n")
 		manPrintFlags(buf, flags)
 		cobra.WriteStringAndCheck(buf, "\n")
+		manPrintFlagsHelp(buf, command, flags)
 	}
-	flags = command.InheritedFlags()
+	flags = docFlags(command, command.InheritedFlags(), header.FlagMetadata)
 	if flags.HasAvailableFlags() {
 		cobra.WriteStringAndCheck(buf, "# OPTIONS INHERITED FROM PARENT COMMANDS\n")
 		manPrintFlags(buf, flags)
 		cobra.WriteStringAndCheck(buf, "\n")
+		manPrintFlagsHelp(buf, command, flags)
+	}
+}
+
+// manPrintFlagsHelp prints the extended help of the flags which have one as subsections.
+func manPrintFlagsHelp(buf io.StringWriter, command *cobra.Command, flags *pflag.FlagSet) {
+	for _, flag := range flagsWithHelp(flags) {
+		cobra.WriteStringAndCheck(buf, "### --"+flag.Name+"\n")
+		if long := command.LocalizedFlagLong(flag); len(long) > 0 {
+			cobra.WriteStringAndCheck(buf, long+"\n\n")
+		}
+		if example := command.LocalizedFlagExample(flag); len(example) > 0 {
+			cobra.WriteStringAndCheck(buf, fmt.Sprintf("```\n%s\n```\n\n", example))
+		}
+	}
+}
+
+// manPrintSection prints the section title with the text of the annotation of the
+// command, or text if the command has no such annotation, if any.
+func manPrintSection(buf io.StringWriter, cmd *cobra.Command, title, annotation, text string) {
+	if value, ok := cmd.Annotations[annotation]; ok {
+		text = value
+	}
+	if len(text) == 0 {
+		return
 	}
+	cobra.WriteStringAndCheck(buf, "# "+title+"\n"+strings.TrimSpace(text)+"\n\n")
+}
+
+// manPrintEnvironment prints the ENVIRONMENT section: the variables configuring cobra on
+// the page of the root command, the variables of the flags set with MarkFlagEnvVar,
+// the variables of the header and the text of ManEnvironmentAnnotation.
+func manPrintEnvironment(buf io.StringWriter, cmd *cobra.Command, header *GenManHeader) {
+	var vars []cobra.EnvVarInfo
+	if !cmd.HasParent() {
+		vars = append(vars, cmd.ConfigEnvVars()...)
+	}
+	if header.FlagMetadata.EnvVars {
+		var flagVars []cobra.EnvVarInfo
+		for _, flags := range []*pflag.FlagSet{cmd.NonInheritedFlags(), cmd.InheritedFlags()} {
+			cmd.DisplayFlags(flags).VisitAll(func(flag *pflag.Flag) {
+				if envVar := cobra.FlagEnvVar(flag); envVar != "" && !flag.Hidden && len(flag.Deprecated) == 0 {
+					flagVars = append(flagVars, cobra.EnvVarInfo{Name: envVar, Description: manFlagEnvVarDescription(flag)})
+				}
+			})
+		}
+		sort.Slice(flagVars, func(i, j int) bool { return flagVars[i].Name < flagVars[j].Name })
+		vars = append(vars, flagVars...)
+	}
+	names := make([]string, 0, len(header.Environment))
+	for name := range header.Environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vars = append(vars, cobra.EnvVarInfo{Name: name, Description: header.Environment[name]})
+	}
+	text := cmd.Annotations[ManEnvironmentAnnotation]
+	if len(vars) == 0 && len(text) == 0 {
+		return
+	}
+
+	cobra.WriteStringAndCheck(buf, "# ENVIRONMENT\n")
+	for _, v := range vars {
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf("**%s**\n: %s\n\n", v.Name, v.Description))
+	}
+	if len(text) > 0 {
+		cobra.WriteStringAndCheck(buf, strings.TrimSpace(text)+"\n\n")
+	}
+}
+
+func manFlagEnvVarDescription(flag *pflag.Flag) string {
+	description := "Sets **--" + flag.Name + "**."
+	if len(flag.Usage) > 0 {
+		description += " " + flag.Usage
+	}
+	return description
 }
 
 func genMan(cmd *cobra.Command, header *GenManHeader) []byte {
@@ -209,11 +313,18 @@ func genMan(cmd *cobra.Command, header *GenManHeader) []byte {
 	buf := new(bytes.Buffer)
 
 	manPreamble(buf, header, cmd, dashCommandName)
-	manPrintOptions(buf, cmd)
-	if len(cmd.Example) > 0 {
+	if _, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]; !isTopic {
+		manPrintOptions(buf, cmd, header)
+	}
+	manPrintSection(buf, cmd, "EXIT STATUS", ManExitStatusAnnotation, header.ExitStatus)
+	manPrintEnvironment(buf, cmd, header)
+	manPrintSection(buf, cmd, "FILES", ManFilesAnnotation, header.Files)
+	manPrintSection(buf, cmd, "BUGS", ManBugsAnnotation, header.Bugs)
+	if example := cmd.DisplayExample(); len(example) > 0 {
 		buf.WriteString("# EXAMPLE\n")
-		buf.WriteString(fmt.Sprintf("```\n%s\n```\n", cmd.Example))
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n", example))
 	}
+	manPrintSection(buf, cmd, "AUTHORS", ManAuthorsAnnotation, header.Authors)
 	if hasSeeAlso(cmd) {
 		buf.WriteString("# SEE ALSO\n")
 		seealsos := make([]string, 0)
@@ -231,7 +342,7 @@ func genMan(cmd *cobra.Command, header *GenManHeader) []byte {
 		children := cmd.Commands()
 		sort.Sort(byName(children))
 		for _, c := range children {
-			if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			if !isDocumented(c) {
 				continue
 			}
 			seealso := fmt.Sprintf("**%s-%s(%s)**", dashCommandName, c.Name(), header.Section)
@@ -229,3 +229,99 @@ func BenchmarkGenManToFile(b *testing.B) {
 		}
 	}
 }
+
+func TestGenManFlagHelp(t *testing.T) {
+	cmd := &cobra.Command{Use: "get", Run: emptyRun}
+	cmd.Flags().String("selector", "", "selector to filter on")
+	assertNoErr(t, cmd.SetFlagHelp("selector", "Matching objects must satisfy all the constraints.", "get --selector app=nginx"))
+	cmd.Flags().Bool("watch", false, "watch for changes")
+
+	buf := new(bytes.Buffer)
+	if err := GenMan(cmd, &GenManHeader{Title: "Project", Section: "1"}, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	checkStringContains(t, output, ".SS --selector")
+	checkStringContains(t, output, "Matching objects must satisfy all the constraints.")
+	checkStringContains(t, output, ".EX\nget --selector app=nginx\n.EE")
+	checkStringOmits(t, output, ".SS --watch")
+}
+
+func TestGenManUseSynopsis(t *testing.T) {
+	cmd := &cobra.Command{Use: "get", Run: emptyRun, UseSynopsis: true, Arguments: []cobra.Argument{{Name: "NAME", Optional: true}}}
+	cmd.Flags().Bool("json", false, "JSON output")
+	cmd.Flags().Bool("yaml", false, "YAML output")
+	cmd.MarkFlagsMutuallyExclusive("json", "yaml")
+
+	buf := new(bytes.Buffer)
+	if err := GenMan(cmd, &GenManHeader{Title: "Project", Section: "1"}, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), ".SH SYNOPSIS\n\\fBget [--json | --yaml] [flags] [NAME]\\fP")
+}
+
+func TestGenManSectionsFromHeader(t *testing.T) {
+	root := &cobra.Command{Use: "my-app", Run: emptyRun}
+	root.Flags().String("token", "", "the API token")
+	assertNoErr(t, root.MarkFlagEnvVar("token", "MY_APP_TOKEN"))
+	sub := &cobra.Command{Use: "sub", Run: emptyRun}
+	root.AddCommand(sub)
+
+	header := &GenManHeader{
+		Title:       "MY-APP",
+		Environment: map[string]string{"HOME": "The home directory."},
+		ExitStatus:  "0 on success, 1 on error.",
+		Files:       "The config file.",
+		Authors:     "The authors.",
+		Bugs:        "Report bugs on the issue tracker.",
+		// The variables of the flags are listed with their metadata
+		FlagMetadata: FlagMetadata{EnvVars: true},
+	}
+	buf := new(bytes.Buffer)
+	if err := GenMan(root, header, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, ".SH EXIT STATUS\n0 on success, 1 on error.\n")
+	checkStringContains(t, output, ".SH ENVIRONMENT\n.TP\n\\fBMY_APP_ACTIVE_HELP\\fP\n")
+	checkStringContains(t, output, ".TP\n\\fBMY_APP_COMPLETION_DESCRIPTIONS\\fP\n")
+	checkStringContains(t, output, ".TP\n\\fBMY_APP_TOKEN\\fP\nSets \\fB--token\\fP\\&. the API token\n")
+	checkStringContains(t, output, ".TP\n\\fBHOME\\fP\nThe home directory.\n")
+	checkStringContains(t, output, ".SH FILES\nThe config file.\n")
+	checkStringContains(t, output, ".SH BUGS\nReport bugs on the issue tracker.\n")
+	checkStringContains(t, output, ".SH AUTHORS\nThe authors.\n")
+
+	// The variables configuring cobra are only documented on the page of the root command
+	buf.Reset()
+	if err := GenMan(sub, header, buf); err != nil {
+		t.Fatal(err)
+	}
+	output = buf.String()
+	checkStringOmits(t, output, "MY_APP_ACTIVE_HELP")
+	checkStringContains(t, output, ".TP\n\\fBHOME\\fP\n")
+}
+
+func TestGenManSectionsFromAnnotations(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "my-app",
+		Run: emptyRun,
+		Annotations: map[string]string{
+			ManExitStatusAnnotation:  "2 on usage errors.",
+			ManEnvironmentAnnotation: "Proxies are read from HTTPS_PROXY.",
+			ManBugsAnnotation:        "",
+		},
+	}
+	header := &GenManHeader{ExitStatus: "0 on success.", Bugs: "Report bugs."}
+	buf := new(bytes.Buffer)
+	if err := GenMan(cmd, header, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, ".SH EXIT STATUS\n2 on usage errors.\n")
+	checkStringOmits(t, output, "0 on success.")
+	checkStringContains(t, output, ".PP\nProxies are read from HTTPS_PROXY.\n")
+	checkStringOmits(t, output, ".SH BUGS")
+	checkStringOmits(t, output, ".SH FILES")
+}
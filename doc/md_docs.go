@@ -15,37 +15,25 @@
 package doc
 
 import (
-	"bytes"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
-	"time"
+	"text/template"
 
 	"github.com/spf13/cobra"
 )
 
 const markdownExtension = ".md"
 
-func printOptions(buf *bytes.Buffer, cmd *cobra.Command, name string) error {
-	flags := cmd.NonInheritedFlags()
-	flags.SetOutput(buf)
-	if flags.HasAvailableFlags() {
-		buf.WriteString("### Options\n\n```\n")
-		flags.PrintDefaults()
-		buf.WriteString("```\n\n")
-	}
-
-	parentFlags := cmd.InheritedFlags()
-	parentFlags.SetOutput(buf)
-	if parentFlags.HasAvailableFlags() {
-		buf.WriteString("### Options inherited from parent commands\n\n```\n")
-		parentFlags.PrintDefaults()
-		buf.WriteString("```\n\n")
-	}
-	return nil
+// GenMarkdownOptions are the options of GenMarkdownFromOpts and GenMarkdownTreeFromOpts.
+type GenMarkdownOptions struct {
+	// FilePrepender returns text to write at the start of the file of the given name.
+	FilePrepender func(string) string
+	// LinkHandler returns the link to the given file name, e.g. "app_get.md".
+	LinkHandler func(string) string
+	// FlagMetadata selects the metadata of the flags appended to their usage.
+	FlagMetadata FlagMetadata
 }
 
 // GenMarkdown creates markdown output.
@@ -55,65 +43,18 @@ func GenMarkdown(cmd *cobra.Command, w io.Writer) error {
 
 // GenMarkdownCustom creates custom markdown output.
 func GenMarkdownCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string) string) error {
-	cmd.InitDefaultHelpCmd()
-	cmd.InitDefaultHelpFlag()
-
-	buf := new(bytes.Buffer)
-	name := cmd.CommandPath()
-
-	buf.WriteString("## " + name + "\n\n")
-	buf.WriteString(cmd.Short + "\n\n")
-	if len(cmd.Long) > 0 {
-		buf.WriteString("### Synopsis\n\n")
-		buf.WriteString(cmd.Long + "\n\n")
-	}
-
-	if cmd.Runnable() {
-		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", cmd.UseLine()))
-	}
-
-	if len(cmd.Example) > 0 {
-		buf.WriteString("### Examples\n\n")
-		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", cmd.Example))
-	}
-
-	if err := printOptions(buf, cmd, name); err != nil {
-		return err
-	}
-	if hasSeeAlso(cmd) {
-		buf.WriteString("### SEE ALSO\n\n")
-		if cmd.HasParent() {
-			parent := cmd.Parent()
-			pname := parent.CommandPath()
-			link := pname + markdownExtension
-			link = strings.ReplaceAll(link, " ", "_")
-			buf.WriteString(fmt.Sprintf("* [%s](%s)\t - %s\n", pname, linkHandler(link), parent.Short))
-			cmd.VisitParents(func(c *cobra.Command) {
-				if c.DisableAutoGenTag {
-					cmd.DisableAutoGenTag = c.DisableAutoGenTag
-				}
-			})
-		}
-
-		children := cmd.Commands()
-		sort.Sort(byName(children))
+	return GenMarkdownFromOpts(cmd, w, GenMarkdownOptions{LinkHandler: linkHandler})
+}
 
-		for _, child := range children {
-			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
-				continue
-			}
-			cname := name + " " + child.Name()
-			link := cname + markdownExtension
-			link = strings.ReplaceAll(link, " ", "_")
-			buf.WriteString(fmt.Sprintf("* [%s](%s)\t - %s\n", cname, linkHandler(link), child.Short))
-		}
-		buf.WriteString("\n")
-	}
-	if !cmd.DisableAutoGenTag {
-		buf.WriteString("###### Auto generated by spf13/cobra on " + time.Now().Format("2-Jan-2006") + "\n")
+// GenMarkdownFromOpts creates markdown output with the given options.
+func GenMarkdownFromOpts(cmd *cobra.Command, w io.Writer, opts GenMarkdownOptions) error {
+	linkHandler := opts.LinkHandler
+	if linkHandler == nil {
+		linkHandler = func(s string) string { return s }
 	}
-	_, err := buf.WriteTo(w)
-	return err
+	tmpl := MarkdownTemplates().Funcs(template.FuncMap{"linkHandler": linkHandler})
+	template.Must(tmpl.Parse(`{{define "link"}}{{linkHandler .File}}{{end}}`))
+	return GenTemplate(cmd, w, TemplateOptions{Templates: tmpl, Extension: markdownExtension, FlagMetadata: opts.FlagMetadata})
 }
 
 // GenMarkdownTree will generate a markdown page for this command and all
@@ -131,11 +72,16 @@ func GenMarkdownTree(cmd *cobra.Command, dir string) error {
 // GenMarkdownTreeCustom is the same as GenMarkdownTree, but
 // with custom filePrepender and linkHandler.
 func GenMarkdownTreeCustom(cmd *cobra.Command, dir string, filePrepender, linkHandler func(string) string) error {
+	return GenMarkdownTreeFromOpts(cmd, dir, GenMarkdownOptions{FilePrepender: filePrepender, LinkHandler: linkHandler})
+}
+
+// GenMarkdownTreeFromOpts is the same as GenMarkdownTree, but with the given options.
+func GenMarkdownTreeFromOpts(cmd *cobra.Command, dir string, opts GenMarkdownOptions) error {
 	for _, c := range cmd.Commands() {
-		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+		if !isDocumented(c) {
 			continue
 		}
-		if err := GenMarkdownTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
+		if err := GenMarkdownTreeFromOpts(c, dir, opts); err != nil {
 			return err
 		}
 	}
@@ -148,11 +94,10 @@ func GenMarkdownTreeCustom(cmd *cobra.Command, dir string, filePrepender, linkHa
 	}
 	defer f.Close()
 
-	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
-		return err
-	}
-	if err := GenMarkdownCustom(cmd, f, linkHandler); err != nil {
-		return err
+	if opts.FilePrepender != nil {
+		if _, err := io.WriteString(f, opts.FilePrepender(filename)); err != nil {
+			return err
+		}
 	}
-	return nil
+	return GenMarkdownFromOpts(cmd, f, opts)
 }
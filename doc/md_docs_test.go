@@ -91,6 +91,25 @@ func TestGenMdNoTag(t *testing.T) {
 	checkStringOmits(t, output, "Auto generated")
 }
 
+func TestGenMdStripsColors(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:   "colored",
+		Short: "a \x1b[1mbold\x1b[0m command",
+		Run:   emptyRun,
+	}
+	cmd.Flags().String("flag", "", "a \x1b[33mcolored\x1b[0m flag")
+
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(cmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "a bold command")
+	checkStringContains(t, output, "a colored flag")
+	checkStringOmits(t, output, "\x1b[")
+}
+
 func TestGenMdTree(t *testing.T) {
 	c := &cobra.Command{Use: "do [OPTIONS] arg1 arg2"}
 	tmpdir, err := os.MkdirTemp("", "test-gen-md-tree")
@@ -123,3 +142,103 @@ func BenchmarkGenMarkdownToFile(b *testing.B) {
 		}
 	}
 }
+
+func TestGenLocalizedTrees(t *testing.T) {
+	c := &cobra.Command{Use: "do [OPTIONS] arg1 arg2", Short: "Do something", Run: emptyRun}
+	c.SetTextProvider(cobra.TextProviderFunc(func(locale string, key cobra.TextKey) (string, bool) {
+		if locale == "de" && key.Field == cobra.TextShort {
+			return "Etwas tun", true
+		}
+		return "", false
+	}))
+	tmpdir, err := os.MkdirTemp("", "test-gen-localized-trees")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	cobra.SetLocale("en")
+	defer cobra.SetLocale("")
+	if err := GenLocalizedTrees([]string{"en", "de"}, tmpdir, func(dir string) error {
+		return GenMarkdownTree(c, dir)
+	}); err != nil {
+		t.Fatalf("GenLocalizedTrees failed: %v", err)
+	}
+
+	for locale, short := range map[string]string{"en": "Do something", "de": "Etwas tun"} {
+		content, err := os.ReadFile(filepath.Join(tmpdir, locale, "do.md"))
+		if err != nil {
+			t.Fatalf("Expected file 'do.md' for locale %q: %v", locale, err)
+		}
+		checkStringContains(t, string(content), short)
+	}
+	if got := cobra.Locale(); got != "en" {
+		t.Errorf("Expected the locale to be restored, got %q", got)
+	}
+
+	cobra.SetLocale("")
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	if err := GenLocalizedTrees([]string{"de"}, tmpdir, func(dir string) error { return nil }); err != nil {
+		t.Fatalf("GenLocalizedTrees failed: %v", err)
+	}
+	t.Setenv("LC_ALL", "ja_JP.UTF-8")
+	if got := cobra.Locale(); got != "ja_JP" {
+		t.Errorf("Expected the locale to be selected from the environment again, got %q", got)
+	}
+}
+
+func TestGenWithHidden(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().String("debug-addr", "", "address of the debug server")
+	_ = rootCmd.Flags().MarkHidden("debug-addr")
+	rootCmd.AddCommand(&cobra.Command{Use: "secret", Short: "a secret command", Hidden: true, Run: emptyRun})
+
+	buf := new(bytes.Buffer)
+	if err := GenWithHidden(rootCmd, func() error {
+		return GenMarkdown(rootCmd, buf)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	checkStringContains(t, output, "address of the debug server (hidden)")
+	checkStringContains(t, output, "a secret command (hidden)")
+
+	buf.Reset()
+	if err := GenMarkdown(rootCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringOmits(t, buf.String(), "debug-addr")
+	checkStringOmits(t, buf.String(), "secret")
+}
+
+func TestGenMdFlagHelp(t *testing.T) {
+	cmd := &cobra.Command{Use: "get", Run: emptyRun}
+	cmd.Flags().String("selector", "", "selector to filter on")
+	if err := cmd.SetFlagHelp("selector", "Matching objects must satisfy all the constraints.", "get --selector app=nginx"); err != nil {
+		t.Fatal(err)
+	}
+	cmd.Flags().Bool("watch", false, "watch for changes")
+
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(cmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	checkStringContains(t, output, "#### --selector\n\nMatching objects must satisfy all the constraints.\n\n```\nget --selector app=nginx\n```\n")
+	checkStringOmits(t, output, "#### --watch")
+}
+
+func TestGenMdStructuredExamples(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "app",
+		Run: emptyRun,
+		Examples: []cobra.Example{
+			{Description: "Say hello", CommandLine: "app --name world", Output: "hello world"},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(cmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), "### Examples\n\n```\n  # Say hello\n  app --name world\n    hello world\n```\n")
+}
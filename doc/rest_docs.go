@@ -15,44 +15,32 @@
 package doc
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
-	"time"
+	"text/template"
 
 	"github.com/spf13/cobra"
 )
 
-func printOptionsReST(buf *bytes.Buffer, cmd *cobra.Command, name string) error {
-	flags := cmd.NonInheritedFlags()
-	flags.SetOutput(buf)
-	if flags.HasAvailableFlags() {
-		buf.WriteString("Options\n")
-		buf.WriteString("~~~~~~~\n\n::\n\n")
-		flags.PrintDefaults()
-		buf.WriteString("\n")
-	}
-
-	parentFlags := cmd.InheritedFlags()
-	parentFlags.SetOutput(buf)
-	if parentFlags.HasAvailableFlags() {
-		buf.WriteString("Options inherited from parent commands\n")
-		buf.WriteString("~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~\n\n::\n\n")
-		parentFlags.PrintDefaults()
-		buf.WriteString("\n")
-	}
-	return nil
-}
-
 // defaultLinkHandler for default ReST hyperlink markup
 func defaultLinkHandler(name, ref string) string {
 	return fmt.Sprintf("`%s <%s.rst>`_", name, ref)
 }
 
+// GenReSTOptions are the options of GenReSTFromOpts and GenReSTTreeFromOpts.
+type GenReSTOptions struct {
+	// FilePrepender returns text to write at the start of the file of the given name.
+	FilePrepender func(string) string
+	// LinkHandler returns the link to the page of a command, given its path and its
+	// reference, e.g. "app_get".
+	LinkHandler func(name, ref string) string
+	// FlagMetadata selects the metadata of the flags appended to their usage.
+	FlagMetadata FlagMetadata
+}
+
 // GenReST creates reStructured Text output.
 func GenReST(cmd *cobra.Command, w io.Writer) error {
 	return GenReSTCustom(cmd, w, defaultLinkHandler)
@@ -60,73 +48,18 @@ func GenReST(cmd *cobra.Command, w io.Writer) error {
 
 // GenReSTCustom creates custom reStructured Text output.
 func GenReSTCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string) error {
-	cmd.InitDefaultHelpCmd()
-	cmd.InitDefaultHelpFlag()
-
-	buf := new(bytes.Buffer)
-	name := cmd.CommandPath()
-
-	short := cmd.Short
-	long := cmd.Long
-	if len(long) == 0 {
-		long = short
-	}
-	ref := strings.ReplaceAll(name, " ", "_")
-
-	buf.WriteString(".. _" + ref + ":\n\n")
-	buf.WriteString(name + "\n")
-	buf.WriteString(strings.Repeat("-", len(name)) + "\n\n")
-	buf.WriteString(short + "\n\n")
-	buf.WriteString("Synopsis\n")
-	buf.WriteString("~~~~~~~~\n\n")
-	buf.WriteString("\n" + long + "\n\n")
-
-	if cmd.Runnable() {
-		buf.WriteString(fmt.Sprintf("::\n\n  %s\n\n", cmd.UseLine()))
-	}
-
-	if len(cmd.Example) > 0 {
-		buf.WriteString("Examples\n")
-		buf.WriteString("~~~~~~~~\n\n")
-		buf.WriteString(fmt.Sprintf("::\n\n%s\n\n", indentString(cmd.Example, "  ")))
-	}
-
-	if err := printOptionsReST(buf, cmd, name); err != nil {
-		return err
-	}
-	if hasSeeAlso(cmd) {
-		buf.WriteString("SEE ALSO\n")
-		buf.WriteString("~~~~~~~~\n\n")
-		if cmd.HasParent() {
-			parent := cmd.Parent()
-			pname := parent.CommandPath()
-			ref = strings.ReplaceAll(pname, " ", "_")
-			buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(pname, ref), parent.Short))
-			cmd.VisitParents(func(c *cobra.Command) {
-				if c.DisableAutoGenTag {
-					cmd.DisableAutoGenTag = c.DisableAutoGenTag
-				}
-			})
-		}
-
-		children := cmd.Commands()
-		sort.Sort(byName(children))
+	return GenReSTFromOpts(cmd, w, GenReSTOptions{LinkHandler: linkHandler})
+}
 
-		for _, child := range children {
-			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
-				continue
-			}
-			cname := name + " " + child.Name()
-			ref = strings.ReplaceAll(cname, " ", "_")
-			buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(cname, ref), child.Short))
-		}
-		buf.WriteString("\n")
-	}
-	if !cmd.DisableAutoGenTag {
-		buf.WriteString("*Auto generated by spf13/cobra on " + time.Now().Format("2-Jan-2006") + "*\n")
+// GenReSTFromOpts creates reStructured Text output with the given options.
+func GenReSTFromOpts(cmd *cobra.Command, w io.Writer, opts GenReSTOptions) error {
+	linkHandler := opts.LinkHandler
+	if linkHandler == nil {
+		linkHandler = defaultLinkHandler
 	}
-	_, err := buf.WriteTo(w)
-	return err
+	tmpl := ReSTTemplates().Funcs(template.FuncMap{"linkHandler": linkHandler})
+	template.Must(tmpl.Parse(`{{define "link"}}{{linkHandler .Path .Ref}}{{end}}`))
+	return GenTemplate(cmd, w, TemplateOptions{Templates: tmpl, Extension: ".rst", FlagMetadata: opts.FlagMetadata})
 }
 
 // GenReSTTree will generate a ReST page for this command and all
@@ -143,11 +76,16 @@ func GenReSTTree(cmd *cobra.Command, dir string) error {
 // GenReSTTreeCustom is the same as GenReSTTree, but
 // with custom filePrepender and linkHandler.
 func GenReSTTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string) string, linkHandler func(string, string) string) error {
+	return GenReSTTreeFromOpts(cmd, dir, GenReSTOptions{FilePrepender: filePrepender, LinkHandler: linkHandler})
+}
+
+// GenReSTTreeFromOpts is the same as GenReSTTree, but with the given options.
+func GenReSTTreeFromOpts(cmd *cobra.Command, dir string, opts GenReSTOptions) error {
 	for _, c := range cmd.Commands() {
-		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+		if !isDocumented(c) {
 			continue
 		}
-		if err := GenReSTTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
+		if err := GenReSTTreeFromOpts(c, dir, opts); err != nil {
 			return err
 		}
 	}
@@ -160,13 +98,12 @@ func GenReSTTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string
 	}
 	defer f.Close()
 
-	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
-		return err
-	}
-	if err := GenReSTCustom(cmd, f, linkHandler); err != nil {
-		return err
+	if opts.FilePrepender != nil {
+		if _, err := io.WriteString(f, opts.FilePrepender(filename)); err != nil {
+			return err
+		}
 	}
-	return nil
+	return GenReSTFromOpts(cmd, f, opts)
 }
 
 // indentString adapted from: https://github.com/kr/text/blob/main/indent.go
@@ -0,0 +1,536 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FrontMatterAnnotationPrefix is the prefix of the annotations of a command which are
+// added to the front matter of its page by HugoFrontMatterTemplate and
+// DocusaurusFrontMatterTemplate, e.g. the annotation "cobra_annotation_front_matter_weight"
+// adds the "weight" field. The values are written as is, as YAML.
+const FrontMatterAnnotationPrefix = "cobra_annotation_front_matter_"
+
+// TemplateCommand describes a command for the templates of GenTemplate and GenTemplateTree.
+type TemplateCommand struct {
+	Name string
+	Path string
+	// Ref is the reference of the command, e.g. "app_get".
+	Ref string
+	// File is the name of the page of the command, e.g. "app_get.md".
+	File string
+	// Depth is 0 for the root of the generated tree, 1 for its children, and so on.
+	Depth   int
+	Aliases []string
+	Short   string
+	Long    string
+	// Topic is the markdown of a help topic added by AddHelpTopics, empty for other commands.
+	Topic    string
+	UseLine  string
+	Example  string
+	Runnable bool
+	// Flags and InheritedFlags are empty for help topics.
+	Flags          []TemplateFlag
+	InheritedFlags []TemplateFlag
+	Annotations    map[string]string
+	// FrontMatter are the annotations whose key starts with FrontMatterAnnotationPrefix,
+	// without the prefix.
+	FrontMatter map[string]string
+	// Parent is nil for the root command. The parent of the root of the generated
+	// tree has no Children.
+	Parent   *TemplateCommand
+	Children []*TemplateCommand
+	// Command is the described command.
+	Command *cobra.Command
+}
+
+// TemplateFlag describes a flag for the templates of GenTemplate and GenTemplateTree.
+type TemplateFlag struct {
+	Name      string
+	Shorthand string
+	Type      string
+	// ValueName is the name of the value of the flag, e.g. "string", empty for boolean flags.
+	ValueName string
+	// Default is empty if the default value is the zero value of the flag.
+	Default string
+	Usage   string
+	Long    string
+	Example string
+	// Required, FileExtensions, ValidValues and EnvVar are the metadata of the flag
+	// selected by TemplateOptions.FlagMetadata, which is appended to Usage too.
+	Required       bool
+	FileExtensions []string
+	ValidValues    []string
+	EnvVar         string
+	// Flag is the described flag, with its usage localized.
+	Flag *pflag.Flag
+}
+
+// TemplatePage is the data of the "page" and "index" templates.
+type TemplatePage struct {
+	// Title is the path of the command of the page, or of the root for the index.
+	Title string
+	// Root is the root of the generated tree.
+	Root *TemplateCommand
+	// Commands are all the commands of the generated tree, depth first. The tree of
+	// GenTemplate is the command of the page and its children.
+	Commands []*TemplateCommand
+	// Command is the command of the page, nil for the index.
+	Command *TemplateCommand
+	// AutoGenTag is empty if DisableAutoGenTag is set on the command of the page.
+	AutoGenTag string
+}
+
+// TemplateOptions are the options of GenTemplate and GenTemplateTree.
+type TemplateOptions struct {
+	// Templates are the templates, e.g. MarkdownTemplates() or ReSTTemplates().
+	Templates *template.Template
+	// Extension is the extension of the pages, e.g. ".md".
+	Extension string
+	// IndexFile is the name of the index page written by GenTemplateTree with
+	// the "index" template, e.g. "_index.md". No index is written if it is empty.
+	IndexFile string
+	// FlagMetadata selects the metadata of the flags set in TemplateFlag and
+	// appended to their usage.
+	FlagMetadata FlagMetadata
+}
+
+var templateFuncs = template.FuncMap{
+	"flagUsages":    templateFlagUsages,
+	"indent":        indentString,
+	"repeat":        strings.Repeat,
+	"join":          strings.Join,
+	"quote":         strconv.Quote,
+	"shiftHeadings": shiftMarkdownHeadings,
+	"tableCell":     markdownTableCell,
+	"underline": func(s, char string) string {
+		return strings.Repeat(char, len(s))
+	},
+}
+
+// MarkdownTemplates returns a new copy of the default markdown templates, with
+// which GenMarkdown and GenMarkdownTree render the pages. The "page" template is executed for
+// each command and the "index" template for the index, both with a TemplatePage.
+// They use the "frontmatter", "options", "flags", "flagsHelp", "seealso" and "link"
+// templates, which can be redefined, e.g. with MarkdownFlagTableTemplate and
+// HugoFrontMatterTemplate:
+//
+//	tmpl := doc.MarkdownTemplates()
+//	template.Must(tmpl.Parse(doc.MarkdownFlagTableTemplate))
+//	template.Must(tmpl.Parse(`{{define "link"}}/commands/{{.Ref}}/{{end}}`))
+//
+// The templates can use the functions:
+//   - flagUsages, which formats a []TemplateFlag as in the help of a command;
+//   - indent, which prefixes each line of a string with a string;
+//   - repeat, join and quote, which are strings.Repeat, strings.Join and strconv.Quote;
+//   - shiftHeadings, which adds levels to the markdown headings of a string;
+//   - tableCell, which escapes a string for a cell of a markdown table;
+//   - underline, which repeats a character as many times as the length of a string.
+func MarkdownTemplates() *template.Template {
+	return template.Must(template.New("markdown").Funcs(templateFuncs).Parse(defaultMarkdownTemplates))
+}
+
+// ReSTTemplates returns a new copy of the default reStructuredText templates, with
+// which GenReST and GenReSTTree render the pages. The templates are the same as the templates
+// of MarkdownTemplates, except "flagsHelp". They can be redefined too, e.g. with
+// ReSTFlagTableTemplate.
+func ReSTTemplates() *template.Template {
+	return template.Must(template.New("rest").Funcs(templateFuncs).Parse(defaultReSTTemplates))
+}
+
+// GenTemplate writes the page of cmd, executing the "page" template of opts.Templates.
+func GenTemplate(cmd *cobra.Command, w io.Writer, opts TemplateOptions) error {
+	pageAutoGenTag, err := templateAutoGenTag()
+	if err != nil {
+		return err
+	}
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+	// The page only links to the children
+	root := newTemplateCommand(cmd, nil, 0, 1, opts)
+	return writeTemplatePage(w, opts.Templates, "page", &TemplatePage{
+		Title:      root.Path,
+		Root:       root,
+		Commands:   templateCommands(root),
+		Command:    root,
+		AutoGenTag: pageAutoGenTag(cmd),
+	})
+}
+
+// GenTemplateTree writes a page for cmd and each of its descendants in the directory dir,
+// e.g. "app_get.md", executing the "page" template of opts.Templates, and the index page
+// with the "index" template if opts.IndexFile is set.
+func GenTemplateTree(cmd *cobra.Command, dir string, opts TemplateOptions) error {
+	pageAutoGenTag, err := templateAutoGenTag()
+	if err != nil {
+		return err
+	}
+	initTemplateTree(cmd)
+	root := newTemplateCommand(cmd, nil, 0, -1, opts)
+	commands := templateCommands(root)
+	for _, c := range commands {
+		page := &TemplatePage{Title: c.Path, Root: root, Commands: commands, Command: c, AutoGenTag: pageAutoGenTag(c.Command)}
+		if err := writeTemplateFile(filepath.Join(dir, c.File), opts.Templates, "page", page); err != nil {
+			return err
+		}
+	}
+	if opts.IndexFile == "" {
+		return nil
+	}
+	index := &TemplatePage{Title: root.Path, Root: root, Commands: commands, AutoGenTag: pageAutoGenTag(cmd)}
+	return writeTemplateFile(filepath.Join(dir, opts.IndexFile), opts.Templates, "index", index)
+}
+
+func writeTemplateFile(filename string, tmpl *template.Template, name string, page *TemplatePage) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeTemplatePage(f, tmpl, name, page)
+}
+
+func writeTemplatePage(w io.Writer, tmpl *template.Template, name string, page *TemplatePage) error {
+	buf := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(buf, name, page); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, cobra.StripANSI(buf.String()))
+	return err
+}
+
+// templateCommands returns root and its descendants, depth first.
+func templateCommands(root *TemplateCommand) []*TemplateCommand {
+	commands := []*TemplateCommand{root}
+	for _, child := range root.Children {
+		commands = append(commands, templateCommands(child)...)
+	}
+	return commands
+}
+
+// templateAutoGenTag returns a function returning the auto generated tag of the page of a command.
+func templateAutoGenTag() (func(cmd *cobra.Command) string, error) {
+	tag, err := autoGenTag()
+	if err != nil {
+		return nil, err
+	}
+	return func(cmd *cobra.Command) string {
+		if autoGenTagDisabled(cmd) {
+			return ""
+		}
+		return tag
+	}, nil
+}
+
+// initTemplateTree initializes the default help command and flag of cmd and its
+// documented descendants, whose pages are generated.
+func initTemplateTree(cmd *cobra.Command) {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+	for _, child := range cmd.Commands() {
+		if isDocumented(child) {
+			initTemplateTree(child)
+		}
+	}
+}
+
+// newTemplateCommand describes cmd and its documented descendants, down to the
+// given number of levels below cmd, or all of them if levels is negative.
+func newTemplateCommand(cmd *cobra.Command, parent *TemplateCommand, depth, levels int, opts TemplateOptions) *TemplateCommand {
+	c := describeTemplateCommand(cmd, depth, opts)
+	c.Parent = parent
+	if parent == nil && cmd.HasParent() {
+		c.Parent = describeTemplateCommand(cmd.Parent(), depth-1, opts)
+	}
+
+	if levels == 0 {
+		return c
+	}
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+	for _, child := range children {
+		if isDocumented(child) {
+			c.Children = append(c.Children, newTemplateCommand(child, c, depth+1, levels-1, opts))
+		}
+	}
+	return c
+}
+
+// describeTemplateCommand describes cmd without its parent and children.
+func describeTemplateCommand(cmd *cobra.Command, depth int, opts TemplateOptions) *TemplateCommand {
+	ref := strings.ReplaceAll(cmd.CommandPath(), " ", "_")
+	c := &TemplateCommand{
+		Name:        cmd.Name(),
+		Path:        cmd.CommandPath(),
+		Ref:         ref,
+		File:        ref + opts.Extension,
+		Depth:       depth,
+		Aliases:     cmd.Aliases,
+		Short:       cmd.DisplayShort(),
+		Long:        cmd.LocalizedLong(),
+		Example:     cmd.DisplayExample(),
+		Runnable:    cmd.Runnable(),
+		Annotations: cmd.Annotations,
+		FrontMatter: map[string]string{},
+		Command:     cmd,
+	}
+	if c.Runnable {
+		c.UseLine = cmd.UseLine()
+	}
+	for key, value := range cmd.Annotations {
+		if strings.HasPrefix(key, FrontMatterAnnotationPrefix) {
+			c.FrontMatter[strings.TrimPrefix(key, FrontMatterAnnotationPrefix)] = value
+		}
+	}
+	if topic, isTopic := cmd.Annotations[cobra.HelpTopicAnnotation]; isTopic {
+		c.Topic = topic
+	} else {
+		c.Flags = templateFlags(cmd, cmd.NonInheritedFlags(), opts.FlagMetadata)
+		c.InheritedFlags = templateFlags(cmd, cmd.InheritedFlags(), opts.FlagMetadata)
+	}
+	return c
+}
+
+func templateFlags(cmd *cobra.Command, flags *pflag.FlagSet, show FlagMetadata) []TemplateFlag {
+	var result []TemplateFlag
+	docFlags(cmd, flags, show).VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || len(flag.Deprecated) > 0 {
+			return
+		}
+		valueName, usage := pflag.UnquoteUsage(flag)
+		metadata := getFlagMetadata(cmd, flag, show)
+		f := TemplateFlag{
+			Name:           flag.Name,
+			Type:           flag.Value.Type(),
+			ValueName:      valueName,
+			Usage:          usage,
+			Long:           cmd.LocalizedFlagLong(flag),
+			Example:        cmd.LocalizedFlagExample(flag),
+			Required:       metadata.required,
+			FileExtensions: metadata.fileExtensions(),
+			ValidValues:    metadata.values,
+			EnvVar:         metadata.envVar,
+			Flag:           flag,
+		}
+		if len(flag.ShorthandDeprecated) == 0 {
+			f.Shorthand = flag.Shorthand
+		}
+		f.Default = flagDefValue(flag)
+		result = append(result, f)
+	})
+	return result
+}
+
+// templateFlagUsages formats flags as in the help of a command.
+func templateFlagUsages(flags []TemplateFlag) string {
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	for _, f := range flags {
+		fs.AddFlag(f.Flag)
+	}
+	return fs.FlagUsages()
+}
+
+var markdownTableCellEscapes = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+// markdownTableCell escapes s for a cell of a markdown table.
+func markdownTableCell(s string) string {
+	return markdownTableCellEscapes.Replace(s)
+}
+
+const defaultMarkdownTemplates = `{{define "page"}}{{template "frontmatter" .}}{{with .Command}}## {{.Path}}
+
+{{.Short}}
+
+{{if .Topic}}{{shiftHeadings .Topic 1}}
+
+{{else if .Long}}### Synopsis
+
+{{.Long}}
+
+{{end}}{{if .Runnable}}` + "```" + `
+{{.UseLine}}
+` + "```" + `
+
+{{end}}{{with .Example}}### Examples
+
+` + "```" + `
+{{.}}
+` + "```" + `
+
+{{end}}{{template "options" .}}{{template "seealso" .}}{{end}}{{with .AutoGenTag}}###### {{.}}
+{{end}}{{end}}
+
+{{define "frontmatter"}}{{end}}
+
+{{define "options"}}{{with .Flags}}### Options
+
+{{template "flags" .}}
+{{template "flagsHelp" .}}{{end}}{{with .InheritedFlags}}### Options inherited from parent commands
+
+{{template "flags" .}}
+{{template "flagsHelp" .}}{{end}}{{end}}
+
+{{define "flags"}}` + "```" + `
+{{flagUsages .}}` + "```" + `
+{{end}}
+
+{{define "flagsHelp"}}{{range .}}{{if or .Long .Example}}#### --{{.Name}}
+
+{{with .Long}}{{.}}
+
+{{end}}{{with .Example}}` + "```" + `
+{{.}}
+` + "```" + `
+
+{{end}}{{end}}{{end}}{{end}}
+
+{{define "seealso"}}{{if or .Parent .Children}}### SEE ALSO
+
+{{with .Parent}}* [{{.Path}}]({{template "link" .}})	 - {{.Short}}
+{{end}}{{range .Children}}* [{{.Path}}]({{template "link" .}})	 - {{.Short}}
+{{end}}
+{{end}}{{end}}
+
+{{define "link"}}{{.File}}{{end}}
+
+{{define "index"}}{{template "frontmatter" .}}# {{.Title}}
+
+{{.Root.Short}}
+
+{{range .Commands}}{{repeat "  " .Depth}}* [{{.Path}}]({{template "link" .}})	 - {{.Short}}
+{{end}}{{with .AutoGenTag}}
+###### {{.}}
+{{end}}{{end}}`
+
+const defaultReSTTemplates = `{{define "page"}}{{template "frontmatter" .}}{{with .Command}}.. _{{.Ref}}:
+
+{{.Path}}
+{{underline .Path "-"}}
+
+{{.Short}}
+
+Synopsis
+~~~~~~~~
+
+
+{{or .Long .Short}}
+
+{{if .Runnable}}::
+
+  {{.UseLine}}
+
+{{end}}{{with .Example}}Examples
+~~~~~~~~
+
+::
+
+{{indent . "  "}}
+
+{{end}}{{template "options" .}}{{template "seealso" .}}{{end}}{{with .AutoGenTag}}*{{.}}*
+{{end}}{{end}}
+
+{{define "frontmatter"}}{{end}}
+
+{{define "options"}}{{with .Flags}}Options
+~~~~~~~
+
+{{template "flags" .}}
+{{end}}{{with .InheritedFlags}}Options inherited from parent commands
+~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+{{template "flags" .}}
+{{end}}{{end}}
+
+{{define "flags"}}::
+
+{{flagUsages .}}{{end}}
+
+{{define "seealso"}}{{if or .Parent .Children}}SEE ALSO
+~~~~~~~~
+
+{{with .Parent}}* {{template "link" .}} 	 - {{.Short}}
+{{end}}{{range .Children}}* {{template "link" .}} 	 - {{.Short}}
+{{end}}
+{{end}}{{end}}
+
+{{define "link"}}` + "`" + `{{.Path}} <{{.File}}>` + "`" + `_{{end}}
+
+{{define "index"}}{{template "frontmatter" .}}{{.Title}}
+{{underline .Title "="}}
+
+{{.Root.Short}}
+
+{{range .Commands}}* {{template "link" .}} 	 - {{.Short}}
+{{end}}{{with .AutoGenTag}}
+*{{.}}*
+{{end}}{{end}}`
+
+// MarkdownFlagTableTemplate redefines the "flags" template of MarkdownTemplates
+// to write the flags as a table.
+const MarkdownFlagTableTemplate = `{{define "flags"}}| Flag | Type | Default | Description |
+|------|------|---------|-------------|
+{{range .}}| {{if .Shorthand}}` + "`-{{.Shorthand}}`" + `, {{end}}` + "`--{{.Name}}`" + ` | {{.ValueName}} | {{tableCell .Default}} | {{tableCell .Usage}} |
+{{end}}{{end}}`
+
+// ReSTFlagTableTemplate redefines the "flags" template of ReSTTemplates
+// to write the flags as a table.
+const ReSTFlagTableTemplate = `{{define "flags"}}.. list-table::
+   :header-rows: 1
+
+   * - Flag
+     - Type
+     - Default
+     - Description
+{{range .}}   * - ` + "``{{if .Shorthand}}-{{.Shorthand}}, {{end}}--{{.Name}}``" + `
+     - {{.ValueName}}
+     - {{.Default}}
+     - {{.Usage}}
+{{end}}{{end}}`
+
+// HugoFrontMatterTemplate redefines the "frontmatter" template to write the
+// front matter of Hugo: the title, description and slug of the page, and the
+// fields set with FrontMatterAnnotationPrefix, e.g. "weight".
+const HugoFrontMatterTemplate = `{{define "frontmatter"}}---
+title: {{quote .Title}}
+{{with .Command}}description: {{quote .Short}}
+slug: {{quote .Ref}}
+{{range $key, $value := .FrontMatter}}{{$key}}: {{$value}}
+{{end}}{{end}}---
+
+{{end}}`
+
+// DocusaurusFrontMatterTemplate redefines the "frontmatter" template to write the
+// front matter of Docusaurus: the id, title, sidebar label and description of the
+// page, and the fields set with FrontMatterAnnotationPrefix, e.g. "sidebar_position".
+const DocusaurusFrontMatterTemplate = `{{define "frontmatter"}}---
+title: {{quote .Title}}
+{{with .Command}}id: {{quote .Ref}}
+sidebar_label: {{quote .Name}}
+description: {{quote .Short}}
+{{range $key, $value := .FrontMatter}}{{$key}}: {{$value}}
+{{end}}{{end}}---
+
+{{end}}`
@@ -0,0 +1,176 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGenTemplateMatchesGenerators(t *testing.T) {
+	rootCmd.DisableAutoGenTag = true
+	defer func() { rootCmd.DisableAutoGenTag = false }()
+
+	tests := []struct {
+		name string
+		opts TemplateOptions
+		gen  func(*cobra.Command, *bytes.Buffer) error
+	}{
+		{"markdown", TemplateOptions{Templates: MarkdownTemplates(), Extension: ".md"}, func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenMarkdown(c, buf)
+		}},
+		{"rest", TemplateOptions{Templates: ReSTTemplates(), Extension: ".rst"}, func(c *cobra.Command, buf *bytes.Buffer) error {
+			return GenReST(c, buf)
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, c := range []*cobra.Command{rootCmd, echoCmd, echoSubCmd} {
+				want := new(bytes.Buffer)
+				if err := tc.gen(c, want); err != nil {
+					t.Fatal(err)
+				}
+				got := new(bytes.Buffer)
+				if err := GenTemplate(c, got, tc.opts); err != nil {
+					t.Fatal(err)
+				}
+				if got.String() != want.String() {
+					t.Errorf("Unexpected page of %q\nExpected:\n%s\nGot:\n%s", c.CommandPath(), want.String(), got.String())
+				}
+			}
+			if echoCmd.DisableAutoGenTag {
+				t.Error("Expected DisableAutoGenTag of the subcommands to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestGenTemplateFlagTable(t *testing.T) {
+	tmpl := MarkdownTemplates()
+	template.Must(tmpl.Parse(MarkdownFlagTableTemplate))
+
+	buf := new(bytes.Buffer)
+	if err := GenTemplate(echoCmd, buf, TemplateOptions{Templates: tmpl, Extension: ".md"}); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "### Options\n\n| Flag | Type | Default | Description |\n")
+	checkStringContains(t, output, "| `-i`, `--intone` | int | 123 | help message for flag intone |\n")
+	checkStringContains(t, output, "| `-b`, `--boolone` |  | true | help message for flag boolone |\n")
+	checkStringContains(t, output, "* [root](root.md)")
+
+	tmpl = ReSTTemplates()
+	template.Must(tmpl.Parse(ReSTFlagTableTemplate))
+	buf.Reset()
+	if err := GenTemplate(echoCmd, buf, TemplateOptions{Templates: tmpl, Extension: ".rst"}); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), "   * - ``-i, --intone``\n     - int\n     - 123\n     - help message for flag intone\n")
+}
+
+func TestGenTemplateFrontMatter(t *testing.T) {
+	key := FrontMatterAnnotationPrefix + "weight"
+	echoCmd.Annotations = map[string]string{key: "10"}
+	defer func() { echoCmd.Annotations = nil }()
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"hugo", HugoFrontMatterTemplate, "---\ntitle: \"root echo\"\ndescription: \"Echo anything to the screen\"\nslug: \"root_echo\"\nweight: 10\n---\n\n## root echo\n"},
+		{"docusaurus", DocusaurusFrontMatterTemplate, "---\ntitle: \"root echo\"\nid: \"root_echo\"\nsidebar_label: \"echo\"\ndescription: \"Echo anything to the screen\"\nweight: 10\n---\n\n## root echo\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := MarkdownTemplates()
+			template.Must(tmpl.Parse(tc.template))
+			buf := new(bytes.Buffer)
+			if err := GenTemplate(echoCmd, buf, TemplateOptions{Templates: tmpl, Extension: ".md"}); err != nil {
+				t.Fatal(err)
+			}
+			checkStringContains(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestGenTemplateTree(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "test-gen-template-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %s", err.Error())
+	}
+	defer os.RemoveAll(tmpdir)
+
+	tmpl := MarkdownTemplates()
+	template.Must(tmpl.Parse(`{{define "link"}}/commands/{{.Ref}}/{{end}}`))
+	opts := TemplateOptions{Templates: tmpl, Extension: ".md", IndexFile: "_index.md"}
+	if err := GenTemplateTree(rootCmd, tmpdir, opts); err != nil {
+		t.Fatalf("GenTemplateTree failed: %s", err.Error())
+	}
+
+	for _, name := range []string{"root.md", "root_echo.md", "root_echo_echosub.md"} {
+		if _, err := os.Stat(filepath.Join(tmpdir, name)); err != nil {
+			t.Errorf("Expected file %q to exist", name)
+		}
+	}
+	page, err := os.ReadFile(filepath.Join(tmpdir, "root_echo.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, string(page), "* [root](/commands/root/)")
+
+	index, err := os.ReadFile(filepath.Join(tmpdir, "_index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(index)
+	checkStringContains(t, output, "# root\n\n"+rootCmd.Short+"\n\n* [root](/commands/root/)")
+	checkStringContains(t, output, "\n  * [root echo](/commands/root_echo/)\t - "+echoCmd.Short+"\n")
+	checkStringContains(t, output, "\n    * [root echo echosub](/commands/root_echo_echosub/)")
+	checkStringOmits(t, output, deprecatedCmd.Short)
+}
+
+func TestGenTemplateOnlyInitializesGeneratedCommand(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	get := &cobra.Command{Use: "get", Run: emptyRun}
+	pods := &cobra.Command{Use: "pods", Run: emptyRun}
+	pods.AddCommand(&cobra.Command{Use: "logs", Run: emptyRun})
+	root.AddCommand(get)
+	get.AddCommand(pods)
+
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(get, buf); err != nil {
+		t.Fatal(err)
+	}
+	if get.Flags().Lookup("help") == nil {
+		t.Error("Expected the help flag of the generated command to be initialized")
+	}
+	for _, c := range []*cobra.Command{root, pods} {
+		for _, child := range c.Commands() {
+			if child.Name() == "help" {
+				t.Errorf("Expected no help command to be added to %q", c.CommandPath())
+			}
+		}
+		if c.Flags().Lookup("help") != nil {
+			t.Errorf("Expected no help flag to be added to %q", c.CommandPath())
+		}
+	}
+}
@@ -15,11 +15,49 @@
 package doc
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// isDocumented returns whether a page is generated for cmd: it must be available
+// and not an additional help topic, unless it is a help topic added by AddHelpTopics.
+func isDocumented(cmd *cobra.Command) bool {
+	if _, ok := cmd.Annotations[cobra.HelpTopicAnnotation]; ok {
+		return !cmd.Hidden
+	}
+	return cmd.IsAvailableCommand() && !cmd.IsAdditionalHelpTopicCommand()
+}
+
+// shiftMarkdownHeadings adds levels to the level of the headings of the markdown src,
+// outside of its code blocks, to nest it in a page. The levels are capped at 6, the
+// lowest level of markdown.
+func shiftMarkdownHeadings(src string, levels int) string {
+	lines := strings.Split(src, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case !inFence && strings.HasPrefix(line, "#"):
+			text := strings.TrimLeft(line, "#")
+			level := minInt(len(line)-len(text)+levels, 6)
+			lines[i] = strings.Repeat("#", level) + text
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Test to see if we have a reason to print See Also information in docs
 // Basically this is a test for a parent command or a subcommand which is
 // both not deprecated and not the autogenerated help command.
@@ -28,7 +66,7 @@ func hasSeeAlso(cmd *cobra.Command) bool {
 		return true
 	}
 	for _, c := range cmd.Commands() {
-		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+		if !isDocumented(c) {
 			continue
 		}
 		return true
@@ -45,8 +83,193 @@ func forceMultiLine(s string) string {
 	return s
 }
 
+// flagsWithHelp returns the visible flags of flags which have an extended help,
+// set with cobra.SetFlagHelp.
+func flagsWithHelp(flags *pflag.FlagSet) []*pflag.Flag {
+	var result []*pflag.Flag
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if !flag.Hidden && len(flag.Deprecated) == 0 && cobra.HasFlagHelp(flag) {
+			result = append(result, flag)
+		}
+	})
+	return result
+}
+
+// flagDefValue returns the default value of the flag, or an empty string if it is
+// the zero value of its type ("", false, 0, 0s, [], ...). The zero values are left
+// to pflag, as for the flag usages, except that the empty slices and maps of the
+// types pflag does not know, such as stringToString, are zero values as well.
+func flagDefValue(flag *pflag.Flag) string {
+	if flag.DefValue == "[]" || flag.DefValue == "map[]" {
+		return ""
+	}
+	f := *flag
+	f.Usage, f.Hidden, f.Deprecated = "", false, ""
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	fs.AddFlag(&f)
+	if !strings.Contains(fs.FlagUsages(), "(default ") {
+		return ""
+	}
+	return flag.DefValue
+}
+
+// generationDate returns the date of the generated docs: the date of the
+// SOURCE_DATE_EPOCH environment variable if it is set, in UTC, so that the docs
+// can be reproduced, or the current date.
+func generationDate() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now(), nil
+	}
+	unixEpoch, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %v", err)
+	}
+	return time.Unix(unixEpoch, 0).UTC(), nil
+}
+
+// autoGenTag returns the text added to the docs unless DisableAutoGenTag is set.
+func autoGenTag() (string, error) {
+	date, err := generationDate()
+	if err != nil {
+		return "", err
+	}
+	return "Auto generated by spf13/cobra on " + date.Format("2-Jan-2006"), nil
+}
+
+// autoGenTagDisabled returns whether DisableAutoGenTag is set on cmd or one of its parents.
+func autoGenTagDisabled(cmd *cobra.Command) bool {
+	disabled := cmd.DisableAutoGenTag
+	cmd.VisitParents(func(c *cobra.Command) {
+		disabled = disabled || c.DisableAutoGenTag
+	})
+	return disabled
+}
+
 type byName []*cobra.Command
 
 func (s byName) Len() int           { return len(s) }
 func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 func (s byName) Less(i, j int) bool { return s[i].Name() < s[j].Name() }
+
+// GenLocalizedTrees calls gen once for each of the given locales, with the
+// locale selected through cobra.SetLocale and a subdirectory of dir named
+// after the locale, which is created if needed. Any of the Gen*Tree functions
+// can be used by gen, e.g.:
+//
+//	GenLocalizedTrees([]string{"en", "de", "ja"}, "docs", func(dir string) error {
+//		return GenMarkdownTree(rootCmd, dir)
+//	})
+//
+// The metadata of the commands is localized through the TextProvider of the
+// commands. The locale in effect before the call is restored afterwards.
+func GenLocalizedTrees(locales []string, dir string, gen func(dir string) error) error {
+	defer cobra.UseLocale("")()
+	for _, locale := range locales {
+		cobra.SetLocale(locale)
+		localeDir := filepath.Join(dir, locale)
+		if err := os.MkdirAll(localeDir, 0o755); err != nil {
+			return err
+		}
+		if err := gen(localeDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenWithHidden calls gen with the hidden commands and flags of the whole tree
+// of cmd revealed and marked as hidden, as for --help-all. Any of the Gen*Tree
+// functions can be used by gen, e.g.:
+//
+//	GenWithHidden(rootCmd, func() error {
+//		return GenMarkdownTree(rootCmd, "internal-docs")
+//	})
+//
+// The hidden commands and flags are hidden again afterwards.
+func GenWithHidden(cmd *cobra.Command, gen func() error) error {
+	defer cmd.RevealHidden()()
+	return gen()
+}
+
+// CheckReproducible calls gen twice, with two new temporary directories, and
+// returns an error if the generated files differ, e.g. in a test:
+//
+//	err := doc.CheckReproducible(func(dir string) error {
+//		return doc.GenManTree(rootCmd, nil, dir)
+//	})
+//
+// Set SOURCE_DATE_EPOCH for the dates of the docs to be reproducible across days.
+func CheckReproducible(gen func(dir string) error) (err error) {
+	var dirs []string
+	defer func() {
+		for _, dir := range dirs {
+			if removeErr := os.RemoveAll(dir); removeErr != nil && err == nil {
+				err = removeErr
+			}
+		}
+	}()
+	for i := 0; i < 2; i++ {
+		dir, err := ioutil.TempDir("", "cobra-doc-")
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, dir)
+		if err := gen(dir); err != nil {
+			return err
+		}
+	}
+
+	first, err := readTree(dirs[0])
+	if err != nil {
+		return err
+	}
+	second, err := readTree(dirs[1])
+	if err != nil {
+		return err
+	}
+	var diffs []string
+	for name, content := range first {
+		if other, ok := second[name]; !ok {
+			diffs = append(diffs, name+" was generated only once")
+		} else if !bytes.Equal(content, other) {
+			diffs = append(diffs, name+" differs")
+		}
+	}
+	for name := range second {
+		if _, ok := first[name]; !ok {
+			diffs = append(diffs, name+" was generated only once")
+		}
+	}
+	if len(diffs) > 0 {
+		sort.Strings(diffs)
+		return fmt.Errorf("docs are not reproducible: %s", strings.Join(diffs, ", "))
+	}
+	return nil
+}
+
+// readTree returns the content of the files under dir, by relative path.
+func readTree(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		var content bytes.Buffer
+		if _, err := content.ReadFrom(f); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content.Bytes()
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,154 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestSourceDateEpoch(t *testing.T) {
+	// 14 Nov 2023 22:13:20 UTC, 15 Nov 2023 in time zones east of UTC+2
+	os.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	// A new command, as the generators propagate DisableAutoGenTag from the parents
+	cmd := &cobra.Command{Use: "app", Run: emptyRun}
+	generators := map[string]func(*bytes.Buffer) error{
+		"markdown": func(buf *bytes.Buffer) error { return GenMarkdown(cmd, buf) },
+		"rest":     func(buf *bytes.Buffer) error { return GenReST(cmd, buf) },
+		"asciidoc": func(buf *bytes.Buffer) error { return GenAsciidoc(cmd, buf) },
+		"man":      func(buf *bytes.Buffer) error { return GenMan(cmd, nil, buf) },
+		"template": func(buf *bytes.Buffer) error {
+			return GenTemplate(cmd, buf, TemplateOptions{Templates: MarkdownTemplates(), Extension: ".md"})
+		},
+	}
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := gen(buf); err != nil {
+				t.Fatal(err)
+			}
+			checkStringContains(t, buf.String(), "14-Nov-2023")
+		})
+	}
+
+	os.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	for name, gen := range generators {
+		if err := gen(new(bytes.Buffer)); err == nil || !strings.Contains(err.Error(), "invalid SOURCE_DATE_EPOCH") {
+			t.Errorf("Expected an invalid SOURCE_DATE_EPOCH error from the %s generator, got %v", name, err)
+		}
+	}
+}
+
+func TestCheckReproducible(t *testing.T) {
+	os.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	generators := map[string]func(dir string) error{
+		"markdown": func(dir string) error { return GenMarkdownTree(rootCmd, dir) },
+		"rest":     func(dir string) error { return GenReSTTree(rootCmd, dir) },
+		"asciidoc": func(dir string) error { return GenAsciidocTree(rootCmd, dir) },
+		"man":      func(dir string) error { return GenManTree(rootCmd, nil, dir) },
+		"yaml":     func(dir string) error { return GenYamlTree(rootCmd, dir) },
+		"json":     func(dir string) error { return GenJSONTree(rootCmd, dir) },
+		"html":     func(dir string) error { return GenHTMLTree(rootCmd, dir) },
+	}
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			if err := CheckReproducible(gen); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	calls := 0
+	err := CheckReproducible(func(dir string) error {
+		calls++
+		if calls == 2 {
+			if err := writeTestFile(filepath.Join(dir, "extra.txt"), "extra"); err != nil {
+				return err
+			}
+		}
+		return writeTestFile(filepath.Join(dir, "page.txt"), fmt.Sprintf("generated %d times", calls))
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected := "docs are not reproducible: extra.txt was generated only once, page.txt differs"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestCheckReproducibleRemovesDirs(t *testing.T) {
+	var dirs []string
+	err := CheckReproducible(func(dir string) error {
+		dirs = append(dirs, dir)
+		if len(dirs) == 2 {
+			return errors.New("generation failed")
+		}
+		return writeTestFile(filepath.Join(dir, "page.txt"), "page")
+	})
+	if err == nil || err.Error() != "generation failed" {
+		t.Errorf("Expected the error of the generation, got %v", err)
+	}
+	if len(dirs) != 2 || dirs[0] == dirs[1] {
+		t.Fatalf("Expected two distinct directories, got %q", dirs)
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", dir, err)
+		}
+	}
+}
+
+func writeTestFile(filename, content string) error {
+	return os.WriteFile(filename, []byte(content), 0o644)
+}
+
+func TestFlagDefValue(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("string", "", "")
+	flags.Bool("bool", false, "")
+	flags.Int("int", 0, "")
+	flags.Duration("duration", 0, "")
+	flags.StringSlice("slice", nil, "")
+	flags.StringToString("map", nil, "")
+	flags.IP("ip", nil, "")
+	flags.Duration("timeout", 30*time.Second, "")
+	flags.StringSlice("names", []string{"a", "b"}, "")
+	flags.String("output", "json", "the `format`")
+	_ = flags.MarkHidden("output")
+
+	expected := map[string]string{
+		"string": "", "bool": "", "int": "", "duration": "", "slice": "", "map": "", "ip": "",
+		"timeout": "30s", "names": "[a,b]", "output": "json",
+	}
+	flags.VisitAll(func(f *pflag.Flag) {
+		if got := flagDefValue(f); got != expected[f.Name] {
+			t.Errorf("Flag %s: expected default %q, got %q", f.Name, expected[f.Name], got)
+		}
+	})
+}
@@ -15,7 +15,6 @@
 package doc
 
 import (
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -27,22 +26,74 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// YamlSchemaVersion is the version of the extended schema of the YAML documents,
+// see GenYamlOptions. It is incremented whenever a change could break their readers.
+const YamlSchemaVersion = 1
+
 type cmdOption struct {
-	Name         string
-	Shorthand    string `yaml:",omitempty"`
-	DefaultValue string `yaml:"default_value,omitempty"`
-	Usage        string `yaml:",omitempty"`
+	Name           string
+	Shorthand      string              `yaml:",omitempty"`
+	Type           string              `yaml:",omitempty"`
+	DefaultValue   string              `yaml:"default_value,omitempty"`
+	Usage          string              `yaml:",omitempty"`
+	Required       bool                `yaml:",omitempty"`
+	File           bool                `yaml:",omitempty"`
+	FileExtensions []string            `yaml:"file_extensions,omitempty"`
+	Directory      bool                `yaml:",omitempty"`
+	ValidValues    []string            `yaml:"valid_values,omitempty"`
+	Completion     bool                `yaml:",omitempty"`
+	EnvVar         string              `yaml:"env_var,omitempty"`
+	Hidden         bool                `yaml:",omitempty"`
+	Deprecated     string              `yaml:",omitempty"`
+	Annotations    map[string][]string `yaml:",omitempty"`
+}
+
+type cmdGroup struct {
+	ID    string
+	Title string
 }
 
 type cmdDoc struct {
+	SchemaVersion    int `yaml:"schema_version,omitempty"`
 	Name             string
-	Synopsis         string      `yaml:",omitempty"`
-	Description      string      `yaml:",omitempty"`
-	Usage            string      `yaml:",omitempty"`
-	Options          []cmdOption `yaml:",omitempty"`
-	InheritedOptions []cmdOption `yaml:"inherited_options,omitempty"`
-	Example          string      `yaml:",omitempty"`
-	SeeAlso          []string    `yaml:"see_also,omitempty"`
+	Aliases          []string          `yaml:",omitempty"`
+	Synopsis         string            `yaml:",omitempty"`
+	Description      string            `yaml:",omitempty"`
+	Usage            string            `yaml:",omitempty"`
+	Hidden           bool              `yaml:",omitempty"`
+	Deprecated       string            `yaml:",omitempty"`
+	Group            string            `yaml:",omitempty"`
+	Annotations      map[string]string `yaml:",omitempty"`
+	Options          []cmdOption       `yaml:",omitempty"`
+	InheritedOptions []cmdOption       `yaml:"inherited_options,omitempty"`
+	Example          string            `yaml:",omitempty"`
+	SeeAlso          []string          `yaml:"see_also,omitempty"`
+	Groups           []cmdGroup        `yaml:",omitempty"`
+	Subcommands      []string          `yaml:",omitempty"`
+	Commands         []*cmdDoc         `yaml:",omitempty"`
+}
+
+// GenYamlOptions are the options of GenYamlFromOpts and GenYamlTreeFromOpts.
+type GenYamlOptions struct {
+	// Extended writes the extended schema of version YamlSchemaVersion, which adds
+	// the schema version, the aliases, hidden and deprecated status, group, annotations,
+	// groups of subcommands and names of the subcommands of the commands, and the type,
+	// required, hidden and deprecated status and annotations of the flags. The hidden
+	// and deprecated commands and flags are included, with their status. The other
+	// metadata of the flags is only included as selected by FlagMetadata.
+	Extended bool
+	// SingleFile writes the whole tree in a single document, in which the documents of
+	// the subcommands are nested in the commands field, and GenYamlTreeFromOpts writes
+	// a single file named after the command, e.g. "app.yaml". It implies Extended.
+	SingleFile bool
+	// FilePrepender returns text to write at the start of the file of the given name.
+	FilePrepender func(string) string
+	// LinkHandler returns the link to the given file name, e.g. "app_get.yaml".
+	LinkHandler func(string) string
+	// FlagMetadata selects the metadata of the flags added to the options: the
+	// required, file, file_extensions, directory, valid_values, completion and
+	// env_var fields.
+	FlagMetadata FlagMetadata
 }
 
 // GenYamlTree creates yaml structured ref files for this command and all descendants
@@ -50,6 +101,7 @@ type cmdDoc struct {
 // correctly if your command names have `-` in them. If you have `cmd` with two
 // subcmds, `sub` and `sub-third`, and `sub` has a subcommand called `third`
 // it is undefined which help output will be in the file `cmd-sub-third.1`.
+// GenYamlTreeFromOpts with SingleFile avoids this.
 func GenYamlTree(cmd *cobra.Command, dir string) error {
 	identity := func(s string) string { return s }
 	emptyStr := func(s string) string { return "" }
@@ -58,12 +110,20 @@ func GenYamlTree(cmd *cobra.Command, dir string) error {
 
 // GenYamlTreeCustom creates yaml structured ref files.
 func GenYamlTreeCustom(cmd *cobra.Command, dir string, filePrepender, linkHandler func(string) string) error {
-	for _, c := range cmd.Commands() {
-		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
-			continue
-		}
-		if err := GenYamlTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
-			return err
+	return GenYamlTreeFromOpts(cmd, dir, GenYamlOptions{FilePrepender: filePrepender, LinkHandler: linkHandler})
+}
+
+// GenYamlTreeFromOpts creates yaml structured ref files for this command and all
+// descendants in the directory given, or a single file if opts.SingleFile is set.
+func GenYamlTreeFromOpts(cmd *cobra.Command, dir string, opts GenYamlOptions) error {
+	if !opts.SingleFile {
+		for _, c := range cmd.Commands() {
+			if !isYamlDocumented(c, opts) {
+				continue
+			}
+			if err := GenYamlTreeFromOpts(c, dir, opts); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -75,13 +135,12 @@ func GenYamlTreeCustom(cmd *cobra.Command, dir string, filePrepender, linkHandle
 	}
 	defer f.Close()
 
-	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
-		return err
-	}
-	if err := GenYamlCustom(cmd, f, linkHandler); err != nil {
-		return err
+	if opts.FilePrepender != nil {
+		if _, err := io.WriteString(f, opts.FilePrepender(filename)); err != nil {
+			return err
+		}
 	}
-	return nil
+	return GenYamlFromOpts(cmd, f, opts)
 }
 
 // GenYaml creates yaml output.
@@ -91,84 +150,146 @@ func GenYaml(cmd *cobra.Command, w io.Writer) error {
 
 // GenYamlCustom creates custom yaml output.
 func GenYamlCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string) string) error {
+	return GenYamlFromOpts(cmd, w, GenYamlOptions{LinkHandler: linkHandler})
+}
+
+// GenYamlFromOpts creates yaml output with the given options. The fields of the
+// documents are always written in the same order, and the options, subcommands
+// and annotations are sorted by name, so that the documents can be diffed.
+func GenYamlFromOpts(cmd *cobra.Command, w io.Writer, opts GenYamlOptions) error {
+	if opts.SingleFile {
+		opts.Extended = true
+	}
+	yamlDoc := genYamlDoc(cmd, opts)
+	if opts.Extended {
+		yamlDoc.SchemaVersion = YamlSchemaVersion
+	}
+
+	final, err := yaml.Marshal(yamlDoc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(final)
+	return err
+}
+
+// isYamlDocumented returns whether cmd is documented, including its hidden and
+// deprecated commands for the extended schema.
+func isYamlDocumented(cmd *cobra.Command, opts GenYamlOptions) bool {
+	if opts.Extended || opts.SingleFile {
+		return isDocumented(cmd) || cmd.Hidden || len(cmd.Deprecated) > 0
+	}
+	return isDocumented(cmd)
+}
+
+func genYamlDoc(cmd *cobra.Command, opts GenYamlOptions) *cmdDoc {
 	cmd.InitDefaultHelpCmd()
 	cmd.InitDefaultHelpFlag()
 
-	yamlDoc := cmdDoc{}
+	yamlDoc := &cmdDoc{}
 	yamlDoc.Name = cmd.CommandPath()
 
-	yamlDoc.Synopsis = forceMultiLine(cmd.Short)
-	yamlDoc.Description = forceMultiLine(cmd.Long)
+	yamlDoc.Synopsis = forceMultiLine(cobra.StripANSI(cmd.LocalizedShort()))
+	yamlDoc.Description = forceMultiLine(cobra.StripANSI(cmd.LocalizedLong()))
 
 	if cmd.Runnable() {
 		yamlDoc.Usage = cmd.UseLine()
 	}
 
-	if len(cmd.Example) > 0 {
-		yamlDoc.Example = cmd.Example
+	if example := cmd.LocalizedExample(); len(example) > 0 {
+		yamlDoc.Example = cobra.StripANSI(example)
 	}
 
-	flags := cmd.NonInheritedFlags()
+	flags := cmd.LocalizedFlags(cmd.NonInheritedFlags())
 	if flags.HasFlags() {
-		yamlDoc.Options = genFlagResult(flags)
+		yamlDoc.Options = genFlagResult(cmd, flags, opts)
 	}
-	flags = cmd.InheritedFlags()
+	flags = cmd.LocalizedFlags(cmd.InheritedFlags())
 	if flags.HasFlags() {
-		yamlDoc.InheritedOptions = genFlagResult(flags)
+		yamlDoc.InheritedOptions = genFlagResult(cmd, flags, opts)
 	}
 
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+
 	if hasSeeAlso(cmd) {
 		result := []string{}
 		if cmd.HasParent() {
 			parent := cmd.Parent()
-			result = append(result, parent.CommandPath()+" - "+parent.Short)
+			result = append(result, parent.CommandPath()+" - "+cobra.StripANSI(parent.LocalizedShort()))
 		}
-		children := cmd.Commands()
-		sort.Sort(byName(children))
 		for _, child := range children {
-			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
+			if !isDocumented(child) {
 				continue
 			}
-			result = append(result, child.CommandPath()+" - "+child.Short)
+			result = append(result, child.CommandPath()+" - "+cobra.StripANSI(child.LocalizedShort()))
 		}
 		yamlDoc.SeeAlso = result
 	}
 
-	final, err := yaml.Marshal(&yamlDoc)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if !opts.Extended && !opts.SingleFile {
+		return yamlDoc
 	}
 
-	if _, err := w.Write(final); err != nil {
-		return err
+	yamlDoc.Aliases = cmd.Aliases
+	yamlDoc.Hidden = cmd.Hidden
+	yamlDoc.Deprecated = cmd.Deprecated
+	yamlDoc.Group = cmd.GroupID
+	yamlDoc.Annotations = cmd.Annotations
+	for _, group := range cmd.Groups() {
+		yamlDoc.Groups = append(yamlDoc.Groups, cmdGroup{ID: group.ID, Title: cmd.LocalizedGroupTitle(group)})
+	}
+	for _, child := range children {
+		if !isYamlDocumented(child, opts) {
+			continue
+		}
+		yamlDoc.Subcommands = append(yamlDoc.Subcommands, child.Name())
+		if opts.SingleFile {
+			yamlDoc.Commands = append(yamlDoc.Commands, genYamlDoc(child, opts))
+		}
 	}
-	return nil
+	return yamlDoc
 }
 
-func genFlagResult(flags *pflag.FlagSet) []cmdOption {
+func genFlagResult(cmd *cobra.Command, flags *pflag.FlagSet, opts GenYamlOptions) []cmdOption {
 	var result []cmdOption
 
+	extended := opts.Extended || opts.SingleFile
 	flags.VisitAll(func(flag *pflag.Flag) {
+		var opt cmdOption
 		// Todo, when we mark a shorthand is deprecated, but specify an empty message.
 		// The flag.ShorthandDeprecated is empty as the shorthand is deprecated.
 		// Using len(flag.ShorthandDeprecated) > 0 can't handle this, others are ok.
 		if !(len(flag.ShorthandDeprecated) > 0) && len(flag.Shorthand) > 0 {
-			opt := cmdOption{
-				flag.Name,
-				flag.Shorthand,
-				flag.DefValue,
-				forceMultiLine(flag.Usage),
+			opt = cmdOption{
+				Name:         flag.Name,
+				Shorthand:    flag.Shorthand,
+				DefaultValue: flag.DefValue,
+				Usage:        forceMultiLine(cobra.StripANSI(flag.Usage)),
 			}
-			result = append(result, opt)
 		} else {
-			opt := cmdOption{
+			opt = cmdOption{
 				Name:         flag.Name,
 				DefaultValue: forceMultiLine(flag.DefValue),
-				Usage:        forceMultiLine(flag.Usage),
+				Usage:        forceMultiLine(cobra.StripANSI(flag.Usage)),
 			}
-			result = append(result, opt)
 		}
+		metadata := getFlagMetadata(cmd, flag, opts.FlagMetadata)
+		opt.Required = metadata.required
+		opt.File = metadata.files
+		opt.FileExtensions = metadata.fileExtensions()
+		opt.Directory = metadata.dir
+		opt.ValidValues = metadata.values
+		opt.Completion = metadata.completion
+		opt.EnvVar = metadata.envVar
+		if extended {
+			opt.Type = flag.Value.Type()
+			opt.Required = isRequiredFlag(flag)
+			opt.Hidden = flag.Hidden
+			opt.Deprecated = flag.Deprecated
+			opt.Annotations = flag.Annotations
+		}
+		result = append(result, opt)
 	})
 
 	return result
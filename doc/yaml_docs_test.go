@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -54,6 +55,25 @@ func TestGenYamlNoTag(t *testing.T) {
 	checkStringOmits(t, output, "Auto generated")
 }
 
+func TestGenYamlStripsColors(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:   "colored",
+		Short: "a \x1b[1mbold\x1b[0m command",
+		Run:   emptyRun,
+	}
+	cmd.Flags().String("flag", "", "a \x1b[33mcolored\x1b[0m flag")
+
+	buf := new(bytes.Buffer)
+	if err := GenYaml(cmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "a bold command")
+	checkStringContains(t, output, "a colored flag")
+	checkStringOmits(t, output, `\e`)
+}
+
 func TestGenYamlTree(t *testing.T) {
 	c := &cobra.Command{Use: "do [OPTIONS] arg1 arg2"}
 
@@ -83,6 +103,113 @@ func TestGenYamlDocRunnable(t *testing.T) {
 	checkStringContains(t, output, "usage: "+rootCmd.Use)
 }
 
+func newYamlExtendedCmd() *cobra.Command {
+	root := &cobra.Command{Use: "app", Short: "the app", Run: emptyRun}
+	root.AddGroup(&cobra.Group{ID: "core", Title: "Core commands:"})
+	root.PersistentFlags().String("config", "", "the config file")
+	get := &cobra.Command{
+		Use:         "get",
+		Aliases:     []string{"g"},
+		Short:       "get things",
+		GroupID:     "core",
+		Annotations: map[string]string{"team": "storage"},
+		Run:         emptyRun,
+	}
+	get.Flags().Int("limit", 10, "the limit")
+	get.Flags().String("namespace", "", "the namespace")
+	_ = get.MarkFlagRequired("namespace")
+	get.AddCommand(&cobra.Command{Use: "pods", Short: "get pods", Run: emptyRun})
+	get.Flags().Bool("secret", false, "a hidden flag")
+	_ = get.Flags().MarkHidden("secret")
+	old := &cobra.Command{Use: "old", Short: "an old command", Deprecated: "use get", Run: emptyRun}
+	internal := &cobra.Command{Use: "internal", Short: "an internal command", Hidden: true, Run: emptyRun}
+	root.AddCommand(get, old, internal)
+	return root
+}
+
+func TestGenYamlExtended(t *testing.T) {
+	root := newYamlExtendedCmd()
+	get, _, _ := root.Find([]string{"get"})
+
+	buf := new(bytes.Buffer)
+	if err := GenYamlFromOpts(get, buf, GenYamlOptions{Extended: true}); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "schema_version: 1\nname: app get\naliases:\n    - g\n")
+	checkStringContains(t, output, "group: core\nannotations:\n    team: storage\n")
+	checkStringContains(t, output, "    - name: limit\n      type: int\n      default_value: \"10\"\n")
+	checkStringContains(t, output, "    - name: secret\n      type: bool\n      default_value: \"false\"\n      usage: a hidden flag\n      hidden: true\n")
+	checkStringContains(t, output, "    - name: namespace\n      type: string\n      usage: the namespace\n      required: true\n")
+	checkStringOmits(t, output, "env_var")
+	checkStringContains(t, output, "    - name: config\n      type: string\n")
+
+	buf.Reset()
+	if err := GenYamlFromOpts(root, buf, GenYamlOptions{Extended: true}); err != nil {
+		t.Fatal(err)
+	}
+	output = buf.String()
+
+	checkStringContains(t, output, "groups:\n    - id: core\n      title: 'Core commands:'\n")
+	checkStringContains(t, output, "subcommands:\n    - get\n    - internal\n    - old\n")
+	checkStringOmits(t, output, "commands:\n    - schema_version")
+
+	// The legacy schema is unchanged
+	buf.Reset()
+	if err := GenYaml(get, buf); err != nil {
+		t.Fatal(err)
+	}
+	output = buf.String()
+	checkStringOmits(t, output, "schema_version")
+	checkStringOmits(t, output, "aliases")
+	checkStringOmits(t, output, "type: int")
+	checkStringOmits(t, output, "required")
+}
+
+func TestGenYamlSingleFile(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "test-gen-yaml-single-file")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %s", err.Error())
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := GenYamlTreeFromOpts(newYamlExtendedCmd(), tmpdir, GenYamlOptions{SingleFile: true}); err != nil {
+		t.Fatalf("GenYamlTreeFromOpts failed: %s", err.Error())
+	}
+	entries, err := os.ReadDir(tmpdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "app.yaml" {
+		t.Fatalf("Expected only the file 'app.yaml', got %v", entries)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpdir, "app.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(content)
+	checkStringContains(t, output, "schema_version: 1\nname: app\n")
+	checkStringContains(t, output, "commands:\n    - name: app get\n")
+	checkStringContains(t, output, "    - name: app internal\n")
+	checkStringContains(t, output, "      hidden: true\n")
+	checkStringContains(t, output, "      deprecated: use get\n")
+	checkStringContains(t, output, "        - name: app get pods\n")
+	if strings.Count(output, "schema_version") != 1 {
+		t.Errorf("Expected a single schema_version, got:\n%s", output)
+	}
+
+	// The documents are identical when generated twice
+	first := new(bytes.Buffer)
+	second := new(bytes.Buffer)
+	assertNoErr(t, GenYamlFromOpts(newYamlExtendedCmd(), first, GenYamlOptions{SingleFile: true}))
+	assertNoErr(t, GenYamlFromOpts(newYamlExtendedCmd(), second, GenYamlOptions{SingleFile: true}))
+	if first.String() != second.String() {
+		t.Error("Expected identical documents")
+	}
+}
+
 func BenchmarkGenYamlToFile(b *testing.B) {
 	file, err := os.CreateTemp("", "")
 	if err != nil {
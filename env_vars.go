@@ -0,0 +1,46 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+// EnvVarInfo describes an environment variable read by the program.
+type EnvVarInfo struct {
+	Name        string
+	Description string
+}
+
+// ConfigEnvVars returns the environment variables configuring the behavior of
+// cobra for the program of the command, e.g. <PROGRAM>_ACTIVE_HELP, to document
+// them. The variables of the pager and of --help-all are only included if the
+// root command enables them.
+func (c *Command) ConfigEnvVars() []EnvVarInfo {
+	root := c.Root()
+	name := root.Name()
+	vars := []EnvVarInfo{
+		{configEnvVar(name, activeHelpEnvVarSuffix), "Set to 0 to disable the Active Help messages of the shell completions."},
+		{configEnvVar(name, configEnvVarSuffixDescriptions), "Set to false to disable the descriptions of the shell completions."},
+		{configEnvVar(name, "HELP_WIDTH"), "The width to which the help is wrapped, 0 to disable wrapping."},
+		{configEnvVar(name, "NO_COLOR"), "Set to disable the colors of the help, as NO_COLOR."},
+	}
+	if root.EnableHelpAll {
+		vars = append(vars, EnvVarInfo{configEnvVar(name, "HELP_ALL"), "Set to true to show the hidden commands and flags in the help."})
+	}
+	if root.PagerOptions.Help || root.PagerOptions.Output {
+		vars = append(vars,
+			EnvVarInfo{configEnvVar(name, "PAGER"), "The pager command, which takes precedence over PAGER. Set to cat to disable the pager."},
+			EnvVarInfo{configEnvVar(name, "NO_PAGER"), "Set to disable the pager, as NO_PAGER."},
+		)
+	}
+	return vars
+}
@@ -0,0 +1,46 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigEnvVars(t *testing.T) {
+	root := &Command{Use: "my-app", Run: emptyRun}
+	child := &Command{Use: "child", Run: emptyRun}
+	root.AddCommand(child)
+
+	names := func(vars []EnvVarInfo) []string {
+		var result []string
+		for _, v := range vars {
+			result = append(result, v.Name)
+		}
+		return result
+	}
+
+	expected := []string{"MY_APP_ACTIVE_HELP", "MY_APP_COMPLETION_DESCRIPTIONS", "MY_APP_HELP_WIDTH", "MY_APP_NO_COLOR"}
+	if got := names(child.ConfigEnvVars()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	root.EnableHelpAll = true
+	root.PagerOptions.Help = true
+	expected = append(expected, "MY_APP_HELP_ALL", "MY_APP_PAGER", "MY_APP_NO_PAGER")
+	if got := names(child.ConfigEnvVars()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
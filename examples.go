@@ -0,0 +1,232 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Example is a structured example of how to use a command. Unlike the free-form
+// Example field of Command, it can be checked with CheckExamples.
+type Example struct {
+	// Description explains what the example does.
+	Description string
+	// CommandLine is the command line of the example, starting with the name of the
+	// root command, e.g. "app get pods -o wide". Single and double quotes, and backslashes,
+	// can be used as in a POSIX shell.
+	CommandLine string
+	// Output is the expected output of the command line, if any.
+	Output string
+}
+
+// DisplayExample returns the examples as shown by the help and the doc generators:
+// Example in the current locale, followed by the structured Examples.
+func (c *Command) DisplayExample() string {
+	example := c.LocalizedExample()
+	if len(c.Examples) == 0 {
+		return example
+	}
+	if example != "" {
+		example += "\n\n"
+	}
+	return example + c.formatExamples()
+}
+
+// formatExamples formats the structured examples of c for the Examples section of the help and docs.
+func (c *Command) formatExamples() string {
+	var sb strings.Builder
+	for i, ex := range c.Examples {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if ex.Description != "" {
+			for _, line := range strings.Split(strings.TrimSpace(ex.Description), "\n") {
+				sb.WriteString("  # " + line + "\n")
+			}
+		}
+		sb.WriteString("  " + ex.CommandLine + "\n")
+		if ex.Output != "" {
+			for _, line := range strings.Split(strings.TrimRight(ex.Output, "\n"), "\n") {
+				sb.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// splitCommandLine splits a command line into words, handling quotes and backslashes
+// as a POSIX shell does, without any expansion.
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// CheckExamplesOptions are the options of CheckExamples.
+type CheckExamplesOptions struct {
+	// Run executes the command line of each example, and compares its standard
+	// output with the expected Output of the example, if any, ignoring the
+	// leading and trailing whitespace.
+	Run bool
+}
+
+// ExampleError reports a structured example which does not match the command tree.
+type ExampleError struct {
+	// CommandPath is the path of the command the example belongs to.
+	CommandPath string
+	Example     Example
+	Err         error
+}
+
+func (e *ExampleError) Error() string {
+	return fmt.Sprintf("example %q of %q: %v", e.Example.CommandLine, e.CommandPath, e.Err)
+}
+
+func (e *ExampleError) Unwrap() error {
+	return e.Err
+}
+
+// CheckExamples checks the structured examples of all the commands of the tree
+// returned by newRoot, so that tests fail when an example references a removed
+// command or flag, or has a wrong number of arguments. It returns an *ExampleError
+// for each invalid example, e.g.:
+//
+//	func TestExamples(t *testing.T) {
+//		for _, err := range cobra.CheckExamples(newRootCmd, cobra.CheckExamplesOptions{}) {
+//			t.Error(err)
+//		}
+//	}
+//
+// Each command line is parsed as Execute would: the command is found, its flags
+// parsed, and its arguments and flags validated. The hooks and Run functions are
+// only called if opts.Run is set. newRoot is called for each example, so that the
+// flags set by an example do not affect the next ones; a function returning the
+// same root command every time can be used too, at the cost of this isolation.
+func CheckExamples(newRoot func() *Command, opts CheckExamplesOptions) []error {
+	var errs []error
+	var walk func(cmd *Command)
+	walk = func(cmd *Command) {
+		for _, ex := range cmd.Examples {
+			if err := checkExample(newRoot(), ex, opts); err != nil {
+				errs = append(errs, &ExampleError{CommandPath: cmd.CommandPath(), Example: ex, Err: err})
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(newRoot())
+	return errs
+}
+
+// checkExample checks a structured example against the tree of root.
+func checkExample(root *Command, ex Example, opts CheckExamplesOptions) error {
+	words, err := splitCommandLine(ex.CommandLine)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 || words[0] != root.Name() {
+		return fmt.Errorf("the command line does not start with %q", root.Name())
+	}
+	args := words[1:]
+
+	// The output is captured, and the settings of root are restored afterwards
+	// as newRoot may return the same root command every time
+	outWriter, errWriter, rootArgs := root.outWriter, root.errWriter, root.args
+	silenceErrors, silenceUsage := root.SilenceErrors, root.SilenceUsage
+	defer func() {
+		root.outWriter, root.errWriter, root.args = outWriter, errWriter, rootArgs
+		root.SilenceErrors, root.SilenceUsage = silenceErrors, silenceUsage
+	}()
+	stdout := new(bytes.Buffer)
+	root.SetOut(stdout)
+	root.SetErr(new(bytes.Buffer))
+
+	if opts.Run {
+		root.SetArgs(args)
+		root.SilenceErrors = true
+		root.SilenceUsage = true
+		if err := root.Execute(); err != nil {
+			return err
+		}
+		if ex.Output != "" && strings.TrimSpace(stdout.String()) != strings.TrimSpace(ex.Output) {
+			return fmt.Errorf("unexpected output:\n%s", stdout.String())
+		}
+		return nil
+	}
+
+	root.initDefaultCmds(args)
+	cmd, flags, err := root.findCommand(args)
+	if err != nil {
+		return err
+	}
+	argWoFlags, _, err := cmd.prepareRun(flags, nil, nil)
+	if errors.Is(err, flag.ErrHelp) {
+		// Such as a subcommand which was removed or renamed
+		if !cmd.Runnable() && len(argWoFlags) > 0 {
+			return errors.New(messagef(MsgUnknownCommand, argWoFlags[0], cmd.CommandPath()) + cmd.findSuggestions(argWoFlags[0]))
+		}
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newExamplesTestCommands() *Command {
+	rootCmd := &Command{Use: "app"}
+	getCmd := &Command{
+		Use:  "get TYPE",
+		Args: ExactArgs(1),
+		Run: func(cmd *Command, args []string) {
+			wide, _ := cmd.Flags().GetBool("wide")
+			cmd.Println("NAME")
+			if wide {
+				cmd.Println("nginx  running")
+			} else {
+				cmd.Println("nginx")
+			}
+		},
+		Examples: []Example{
+			{Description: "List the pods", CommandLine: "app get pods"},
+			{Description: "List the pods with their status", CommandLine: "app get pods --wide", Output: "NAME\nnginx  running"},
+		},
+	}
+	getCmd.Flags().Bool("wide", false, "show more columns")
+	configCmd := &Command{Use: "config"}
+	configCmd.AddCommand(&Command{Use: "view", Args: NoArgs, Run: emptyRun})
+	execCmd := &Command{Use: "exec COMMAND [ARG...]", Args: MinimumNArgs(1), DisableFlagParsing: true, Run: emptyRun}
+	rootCmd.AddCommand(getCmd, configCmd, execCmd)
+	return rootCmd
+}
+
+func TestExamplesInHelp(t *testing.T) {
+	rootCmd := newExamplesTestCommands()
+	getCmd, _, _ := rootCmd.Find([]string{"get"})
+	getCmd.Example = "  app get nodes"
+
+	output, err := executeCommand(rootCmd, "get", "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, ""+
+		"Examples:\n"+
+		"  app get nodes\n"+
+		"\n"+
+		"  # List the pods\n"+
+		"  app get pods\n"+
+		"\n"+
+		"  # List the pods with their status\n"+
+		"  app get pods --wide\n"+
+		"    NAME\n"+
+		"    nginx  running\n"+
+		"\n"+
+		"Flags:")
+}
+
+func TestExamplesNotInLocalizedExample(t *testing.T) {
+	rootCmd := newExamplesTestCommands()
+	getCmd, _, _ := rootCmd.Find([]string{"get"})
+	getCmd.Example = "  app get nodes"
+
+	if got := getCmd.LocalizedExample(); got != "  app get nodes" {
+		t.Errorf("Expected LocalizedExample to be Example, got %q", got)
+	}
+	if got := getCmd.HelpInfo().Example; got != "  app get nodes" {
+		t.Errorf("Expected the example of HelpInfo to be Example, got %q", got)
+	}
+	checkStringContains(t, getCmd.DisplayExample(), "  app get nodes\n\n  # List the pods\n  app get pods\n")
+}
+
+func TestCheckExamples(t *testing.T) {
+	if errs := CheckExamples(newExamplesTestCommands, CheckExamplesOptions{}); len(errs) > 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	newRoot := func() *Command {
+		rootCmd := newExamplesTestCommands()
+		rootCmd.Examples = []Example{{CommandLine: "app config view"}, {CommandLine: "app exec ls -l"}}
+		return rootCmd
+	}
+	if errs := CheckExamples(newRoot, CheckExamplesOptions{}); len(errs) > 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	if errs := CheckExamples(newExamplesTestCommands, CheckExamplesOptions{Run: true}); len(errs) > 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestCheckExamplesErrors(t *testing.T) {
+	testCases := []struct {
+		commandLine string
+		expected    string
+	}{
+		{"app get", "accepts 1 arg(s), received 0"},
+		{"app get pods --wid", "unknown flag: --wid"},
+		{"app gett pods", `unknown command "gett" for "app"`},
+		{"kubectl get pods", `the command line does not start with "app"`},
+		{"app get 'pods", "unterminated quote or escape"},
+		{"app config set color", `unknown command "set" for "app config"`},
+		{"app exec", "requires at least 1 arg(s), only received 0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.commandLine, func(t *testing.T) {
+			newRoot := func() *Command {
+				rootCmd := newExamplesTestCommands()
+				rootCmd.Examples = []Example{{CommandLine: tc.commandLine}}
+				return rootCmd
+			}
+			errs := CheckExamples(newRoot, CheckExamplesOptions{})
+			if len(errs) != 1 {
+				t.Fatalf("Expected one error, got %v", errs)
+			}
+			var exampleErr *ExampleError
+			if !errors.As(errs[0], &exampleErr) || exampleErr.CommandPath != "app" {
+				t.Errorf("Expected an ExampleError for app, got %#v", errs[0])
+			}
+			checkStringContains(t, errs[0].Error(), tc.expected)
+		})
+	}
+}
+
+func TestCheckExamplesRunOutput(t *testing.T) {
+	newRoot := func() *Command {
+		rootCmd := newExamplesTestCommands()
+		rootCmd.Examples = []Example{{CommandLine: "app get pods", Output: "NAME\nnginx  running"}}
+		return rootCmd
+	}
+	if errs := CheckExamples(newRoot, CheckExamplesOptions{}); len(errs) > 0 {
+		t.Errorf("Unexpected errors without running: %v", errs)
+	}
+	errs := CheckExamples(newRoot, CheckExamplesOptions{Run: true})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "unexpected output") {
+		t.Errorf("Expected an unexpected output error, got %v", errs)
+	}
+}
+
+func TestCheckExamplesRestoresRoot(t *testing.T) {
+	rootCmd := newExamplesTestCommands()
+	out := new(bytes.Buffer)
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{"get", "nodes"})
+	newRoot := func() *Command { return rootCmd }
+
+	for _, opts := range []CheckExamplesOptions{{}, {Run: true}} {
+		if errs := CheckExamples(newRoot, opts); len(errs) > 0 {
+			t.Errorf("Unexpected errors: %v", errs)
+		}
+		if rootCmd.OutOrStdout() != out || rootCmd.errWriter != nil {
+			t.Errorf("Expected the output of the root to be restored with %+v", opts)
+		}
+		if !reflect.DeepEqual(rootCmd.args, []string{"get", "nodes"}) {
+			t.Errorf("Expected the args of the root to be restored with %+v, got %q", opts, rootCmd.args)
+		}
+		if rootCmd.SilenceErrors || rootCmd.SilenceUsage {
+			t.Errorf("Expected the silence settings of the root to be restored with %+v", opts)
+		}
+	}
+	if out.Len() > 0 {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected []string
+	}{
+		{"app get pods", []string{"app", "get", "pods"}},
+		{"  app   get\tpods ", []string{"app", "get", "pods"}},
+		{`app -l 'env!=prod' --name "my pod"`, []string{"app", "-l", "env!=prod", "--name", "my pod"}},
+		{`app a\ b '' "it's"`, []string{"app", "a b", "", "it's"}},
+	}
+	for _, tc := range testCases {
+		got, err := splitCommandLine(tc.line)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tc.line, err)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected %q for %q, got %q", tc.expected, tc.line, got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	flag "github.com/spf13/pflag"
+)
+
+// FlagEnvVarAnnotation is the annotation of a flag naming the environment
+// variable which sets it, see MarkFlagEnvVar.
+const FlagEnvVarAnnotation = "cobra_annotation_flag_env_var"
+
+// MarkFlagEnvVar records that the named flag can be set with the environment
+// variable envVar, so that the generated documentation mentions it. Cobra does
+// not read the variable itself: bind it to the flag, e.g. with viper.BindEnv.
+func (c *Command) MarkFlagEnvVar(name, envVar string) error {
+	return MarkFlagEnvVar(c.Flags(), name, envVar)
+}
+
+// MarkPersistentFlagEnvVar records that the named persistent flag can be set
+// with the environment variable envVar, see MarkFlagEnvVar.
+func (c *Command) MarkPersistentFlagEnvVar(name, envVar string) error {
+	return MarkFlagEnvVar(c.PersistentFlags(), name, envVar)
+}
+
+// MarkFlagEnvVar records that the named flag can be set with the environment
+// variable envVar, see Command.MarkFlagEnvVar.
+func MarkFlagEnvVar(flags *flag.FlagSet, name, envVar string) error {
+	return flags.SetAnnotation(name, FlagEnvVarAnnotation, []string{envVar})
+}
+
+// FlagEnvVar returns the environment variable recorded for the flag with
+// MarkFlagEnvVar, or "" if there is none.
+func FlagEnvVar(f *flag.Flag) string {
+	if envVar := f.Annotations[FlagEnvVarAnnotation]; len(envVar) > 0 {
+		return envVar[0]
+	}
+	return ""
+}
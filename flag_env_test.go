@@ -0,0 +1,40 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import "testing"
+
+func TestMarkFlagEnvVar(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().String("token", "", "the token")
+	c.PersistentFlags().String("config", "", "the config file")
+	c.Flags().Bool("verbose", false, "verbose output")
+
+	if err := c.MarkFlagEnvVar("token", "APP_TOKEN"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MarkPersistentFlagEnvVar("config", "APP_CONFIG"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MarkFlagEnvVar("unknown", "APP_UNKNOWN"); err == nil {
+		t.Error("Expected an error for an unknown flag")
+	}
+
+	for name, expected := range map[string]string{"token": "APP_TOKEN", "config": "APP_CONFIG", "verbose": ""} {
+		if got := FlagEnvVar(c.Flag(name)); got != expected {
+			t.Errorf("Expected %q for --%s, got %q", expected, name, got)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package cobra
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -158,7 +159,7 @@ func validateRequiredFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(unset)
-		return fmt.Errorf("if any flags in the group [%v] are set they must all be set; missing %v", flagList, unset)
+		return errors.New(messagef(MsgFlagGroupRequired, flagList, unset))
 	}
 
 	return nil
@@ -180,7 +181,7 @@ func validateOneRequiredFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(set)
-		return fmt.Errorf("at least one of the flags in the group [%v] is required", flagList)
+		return errors.New(messagef(MsgFlagGroupOneRequired, flagList))
 	}
 	return nil
 }
@@ -201,7 +202,7 @@ func validateExclusiveFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(set)
-		return fmt.Errorf("if any flags in the group [%v] are set none of the others can be; %v were all set", flagList, set)
+		return errors.New(messagef(MsgFlagGroupExclusive, flagList, set))
 	}
 	return nil
 }
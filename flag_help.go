@@ -0,0 +1,225 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Annotations holding the extended help of a flag, set with SetFlagHelp.
+const (
+	FlagLongAnnotation    = "cobra_annotation_flag_long"
+	FlagExampleAnnotation = "cobra_annotation_flag_example"
+)
+
+// SetFlagHelp sets the extended help of the named flag: a long description and
+// examples, which do not fit in its one-line usage. They are shown by
+// 'cmd --help=<flag>' and 'help cmd --<flag>', and included in the generated docs.
+func (c *Command) SetFlagHelp(name, long, example string) error {
+	return SetFlagHelp(c.Flags(), name, long, example)
+}
+
+// SetPersistentFlagHelp sets the extended help of the named persistent flag: a long
+// description and examples, which do not fit in its one-line usage. They are shown by
+// 'cmd --help=<flag>' and 'help cmd --<flag>', and included in the generated docs.
+func (c *Command) SetPersistentFlagHelp(name, long, example string) error {
+	return SetFlagHelp(c.PersistentFlags(), name, long, example)
+}
+
+// SetFlagHelp sets the extended help of the named flag: a long description and
+// examples, which do not fit in its one-line usage. They are shown by
+// 'cmd --help=<flag>' and 'help cmd --<flag>', and included in the generated docs.
+func SetFlagHelp(flags *flag.FlagSet, name, long, example string) error {
+	if err := flags.SetAnnotation(name, FlagLongAnnotation, []string{long}); err != nil {
+		return err
+	}
+	return flags.SetAnnotation(name, FlagExampleAnnotation, []string{example})
+}
+
+// HasFlagHelp returns whether the flag has an extended help set with SetFlagHelp.
+func HasFlagHelp(f *flag.Flag) bool {
+	return flagAnnotation(f, FlagLongAnnotation) != "" || flagAnnotation(f, FlagExampleAnnotation) != ""
+}
+
+func flagAnnotation(f *flag.Flag, annotation string) string {
+	if values := f.Annotations[annotation]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// LocalizedFlagLong returns the long description of the given flag, set with
+// SetFlagHelp, in the current locale.
+func (c *Command) LocalizedFlagLong(f *flag.Flag) string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextFlagLong, Name: f.Name}, flagAnnotation(f, FlagLongAnnotation))
+}
+
+// LocalizedFlagExample returns the examples of the given flag, set with
+// SetFlagHelp, in the current locale.
+func (c *Command) LocalizedFlagExample(f *flag.Flag) string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextFlagExample, Name: f.Name}, flagAnnotation(f, FlagExampleAnnotation))
+}
+
+// lookupFlagForHelp finds the flag of c named name, with or without dashes, either by
+// its name or by its shorthand.
+func (c *Command) lookupFlagForHelp(name string) *flag.Flag {
+	c.mergePersistentFlags()
+	name = strings.TrimLeft(name, "-")
+	if f := c.Flags().Lookup(name); f != nil {
+		return f
+	}
+	if len(name) == 1 {
+		return c.Flags().ShorthandLookup(name)
+	}
+	return nil
+}
+
+// WriteFlagHelp writes the extended help of the flag of c named name: its usage line,
+// followed by its long description and examples if it has any.
+func (c *Command) WriteFlagHelp(w io.Writer, name string) error {
+	f := c.lookupFlagForHelp(name)
+	if f == nil {
+		return c.unknownFlagHelpError(name)
+	}
+
+	fs := flag.NewFlagSet(c.DisplayName(), flag.ContinueOnError)
+	fs.AddFlag(f)
+	width := c.HelpWidth()
+	var sb strings.Builder
+	sb.WriteString(c.StyleFlags(trimRightSpace(c.DisplayFlags(fs).FlagUsagesWrapped(width))) + "\n")
+	if long := c.LocalizedFlagLong(f); long != "" {
+		sb.WriteString("\n" + wrapText(width, trimRightSpace(long)) + "\n")
+	}
+	if example := c.LocalizedFlagExample(f); example != "" {
+		sb.WriteString("\n" + c.StyleHeading(Message(MsgExamples)) + "\n" + trimRightSpace(example) + "\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// unknownFlagHelpError returns the error for the extended help of a flag c does not have.
+func (c *Command) unknownFlagHelpError(name string) error {
+	name = strings.TrimLeft(name, "-")
+	suggestions := ""
+	if c.suggestionsEnabled() {
+		suggestions = formatSuggestions(c.FlagSuggestionsFor("--" + name))
+	}
+	return errors.New(messagef(MsgUnknownFlagHelpTopic, name, c.CommandPath()) + suggestions)
+}
+
+// helpFlagValue is the value of the default help flag: a boolean which also
+// accepts the name of a flag, as in --help=selector, to show the extended help of that flag.
+type helpFlagValue struct {
+	cmd  *Command
+	help bool
+	flag string
+}
+
+func (h *helpFlagValue) Set(s string) error {
+	// A flag named like a boolean, e.g. with shorthand t or f, takes precedence
+	if h.cmd != nil && h.cmd.lookupFlagForHelp(s) != nil {
+		h.help, h.flag = true, s
+		return nil
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		h.help, h.flag = b, ""
+		return nil
+	}
+	h.help, h.flag = true, s
+	return nil
+}
+
+func (h *helpFlagValue) String() string { return strconv.FormatBool(h.help) }
+
+func (h *helpFlagValue) Type() string { return "bool" }
+
+func (h *helpFlagValue) IsBoolFlag() bool { return true }
+
+// helpFlagTopic returns the flag name given with --help=<flag>, if any.
+func (c *Command) helpFlagTopic() string {
+	f := c.Flags().Lookup(helpFlagName)
+	if f == nil {
+		return ""
+	}
+	if v, ok := f.Value.(*helpFlagValue); ok && v.help {
+		return v.flag
+	}
+	return ""
+}
+
+// helpCmdFlagErrorFunc lets 'help cmd --flag' show the extended help of a flag of cmd
+// instead of failing on an unknown flag of the help command. Other errors are handled
+// by the FlagErrorFunc of the parent.
+func helpCmdFlagErrorFunc(c *Command, err error) error {
+	// Suggestions may follow the error of pflag
+	msg := strings.SplitN(err.Error(), "\n", 2)[0]
+	name := ""
+	if m := unknownFlagErrRegexp.FindStringSubmatch(msg); m != nil {
+		name = strings.SplitN(m[1], "=", 2)[0]
+	} else if m := unknownShorthandErrRegexp.FindStringSubmatch(msg); m != nil && len(m[2]) == 1 {
+		name = m[1]
+	}
+	if name != "" {
+		if cmd, _, e := c.Root().Find(c.Flags().Args()); e == nil && cmd != nil && cmd.lookupFlagForHelp(name) != nil {
+			return c.writeHelpCmdFlagHelp(cmd, name)
+		}
+	}
+	return c.Parent().FlagErrorFunc()(c, err)
+}
+
+// helpCmdFlagTopic returns the command and the name of the flag for 'help cmd --flag'
+// when the help command c has a flag of the same name or shorthand, such as --all or -k.
+// Flag topics are resolved against cmd before the flags of the help command.
+func (c *Command) helpCmdFlagTopic(args []string) (*Command, string) {
+	var names, cmdArgs []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			names = append(names, strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0])
+		} else {
+			cmdArgs = append(cmdArgs, arg)
+		}
+	}
+	if len(cmdArgs) == 0 {
+		return nil, ""
+	}
+	for _, name := range names {
+		// Other flags are handled by helpCmdFlagErrorFunc
+		if f := c.lookupFlagForHelp(name); f == nil || f.Name == helpFlagName {
+			continue
+		}
+		if cmd, _, err := c.Root().Find(cmdArgs); err == nil && cmd != nil && cmd.lookupFlagForHelp(name) != nil {
+			return cmd, name
+		}
+	}
+	return nil, ""
+}
+
+// writeHelpCmdFlagHelp writes the extended help of the flag of cmd named name for the help command c.
+func (c *Command) writeHelpCmdFlagHelp(cmd *Command, name string) error {
+	cmd.InitDefaultHelpFlag()
+	var err error
+	c.withPager(c.Root().PagerOptions.Help, func() {
+		err = cmd.WriteFlagHelp(c.OutOrStdout(), name)
+	})
+	return err
+}
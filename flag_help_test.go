@@ -0,0 +1,166 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func newFlagHelpTestCommands() *Command {
+	rootCmd := &Command{Use: "root"}
+	getCmd := &Command{Use: "get", Run: emptyRun}
+	getCmd.Flags().StringP("selector", "l", "", "selector (label query) to filter on")
+	_ = getCmd.SetFlagHelp("selector",
+		"Supports '=', '==', and '!='. Matching objects must satisfy all of the specified label constraints.",
+		"  root get -l app=nginx\n  root get -l 'env!=prod'")
+	getCmd.Flags().Bool("watch", false, "watch for changes")
+	rootCmd.AddCommand(getCmd)
+	return rootCmd
+}
+
+func TestHelpFlagWithFlagName(t *testing.T) {
+	setEnv(t, "COBRA_HELP_WIDTH", "80")
+	for _, args := range [][]string{
+		{"get", "--help=selector"},
+		{"get", "--help=--selector"},
+		{"get", "--help=l"},
+		{"help", "get", "--selector"},
+		{"help", "get", "-l"},
+	} {
+		output, err := executeCommand(newFlagHelpTestCommands(), args...)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", args, err)
+		}
+		expected := "" +
+			"  -l, --selector string   selector (label query) to filter on\n" +
+			"\n" +
+			"Supports '=', '==', and '!='. Matching objects must satisfy all of the specified\n" +
+			"label constraints.\n" +
+			"\n" +
+			"Examples:\n" +
+			"  root get -l app=nginx\n" +
+			"  root get -l 'env!=prod'\n"
+		if output != expected {
+			t.Errorf("Unexpected output for %v:\nexpected:\n%q\ngot:\n%q", args, expected, output)
+		}
+	}
+}
+
+func TestHelpCmdWithFlagNamedLikeHelpCmdFlag(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	getCmd := &Command{Use: "get", Run: emptyRun}
+	getCmd.Flags().BoolP("all", "k", false, "list all the objects")
+	getCmd.Flags().Bool("tree", false, "show the objects as a tree")
+	rootCmd.AddCommand(getCmd)
+
+	for _, args := range [][]string{{"help", "get", "--all"}, {"help", "get", "-k"}} {
+		output, err := executeCommand(rootCmd, args...)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", args, err)
+		}
+		if expected := "  -k, --all   list all the objects\n"; output != expected {
+			t.Errorf("Unexpected output for %v: expected %q, got %q", args, expected, output)
+		}
+	}
+	output, err := executeCommand(rootCmd, "help", "get", "--tree")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "show the objects as a tree")
+	checkStringOmits(t, output, "Usage:")
+
+	// Flags of the help command which get does not have keep their meaning
+	output, err = executeCommand(rootCmd, "help", "get", "--depth", "1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Usage:")
+}
+
+func TestHelpFlagWithBooleanLikeFlagName(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	getCmd := &Command{Use: "get", Run: emptyRun}
+	getCmd.Flags().BoolP("force", "f", false, "force the operation")
+	getCmd.Flags().StringP("template", "t", "", "template of the output")
+	rootCmd.AddCommand(getCmd)
+
+	for args, expected := range map[string]string{
+		"--help=f": "  -f, --force   force the operation\n",
+		"--help=t": "  -t, --template string   template of the output\n",
+	} {
+		output, err := executeCommand(rootCmd, "get", args)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", args, err)
+		}
+		if output != expected {
+			t.Errorf("Unexpected output for %s: expected %q, got %q", args, expected, output)
+		}
+	}
+}
+
+func TestHelpFlagWithoutExtendedHelp(t *testing.T) {
+	output, err := executeCommand(newFlagHelpTestCommands(), "get", "--help=watch")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "      --watch   watch for changes\n"; output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestHelpFlagStillBoolean(t *testing.T) {
+	for _, args := range [][]string{{"get", "--help"}, {"get", "-h"}, {"get", "--help=true"}} {
+		output, err := executeCommand(newFlagHelpTestCommands(), args...)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", args, err)
+		}
+		checkStringContains(t, output, "Usage:")
+	}
+	output, err := executeCommand(newFlagHelpTestCommands(), "get", "--help=false")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "Usage:")
+}
+
+func TestHelpFlagWithUnknownFlagName(t *testing.T) {
+	_, err := executeCommand(newFlagHelpTestCommands(), "get", "--help=selectr")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringContains(t, err.Error(), `unknown flag "selectr" for "root get"`)
+	checkStringContains(t, err.Error(), "--selector")
+}
+
+func TestHelpCmdWithUnknownFlag(t *testing.T) {
+	_, err := executeCommand(newFlagHelpTestCommands(), "help", "get", "--nope")
+	if err == nil || !strings.HasPrefix(err.Error(), "unknown flag: --nope") {
+		t.Errorf("Expected an unknown flag error, got %v", err)
+	}
+}
+
+func TestFlagHelpCompletionDescription(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().String("template", "", "")
+	_ = rootCmd.SetFlagHelp("template", "Go template to format the output.\nSee the documentation of text/template.", "")
+
+	output, err := executeCommand(rootCmd, ShellCompRequestCmd, "--t")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--template\tGo template to format the output.\n")
+	checkStringOmits(t, output, "text/template")
+}
@@ -0,0 +1,111 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"sync"
+
+	flag "github.com/spf13/pflag"
+)
+
+// FlagVisibilityFunc returns whether a flag applies to the command, given the
+// values of its other flags as parsed so far (or their defaults).
+type FlagVisibilityFunc func(cmd *Command) bool
+
+// Global map of flag visibility functions. Make sure to use flagVisibilityMutex before you try to read and write from it.
+var flagVisibilityFunctions = map[*flag.Flag]FlagVisibilityFunc{}
+
+// lock for reading and writing from flagVisibilityFunctions
+var flagVisibilityMutex = &sync.RWMutex{}
+
+// RegisterFlagVisibilityFunc registers a function deciding whether the flag applies
+// to the command depending on the other flags given. The flag is omitted from the
+// help and from shell completion when the function returns false.
+// For example, to only show --replicas when --target=k8s is given:
+//
+//	cmd.RegisterFlagVisibilityFunc("replicas", cobra.FlagValueIn("target", "k8s"))
+func (c *Command) RegisterFlagVisibilityFunc(flagName string, f FlagVisibilityFunc) error {
+	flag := c.Flag(flagName)
+	if flag == nil {
+		return fmt.Errorf("RegisterFlagVisibilityFunc: flag '%s' does not exist", flagName)
+	}
+	flagVisibilityMutex.Lock()
+	defer flagVisibilityMutex.Unlock()
+
+	if _, exists := flagVisibilityFunctions[flag]; exists {
+		return fmt.Errorf("RegisterFlagVisibilityFunc: flag '%s' already registered", flagName)
+	}
+	flagVisibilityFunctions[flag] = f
+	return nil
+}
+
+// GetFlagVisibilityFunc returns the visibility function for the given flag of the command, if available.
+func (c *Command) GetFlagVisibilityFunc(flagName string) (FlagVisibilityFunc, bool) {
+	flag := c.Flag(flagName)
+	if flag == nil {
+		return nil, false
+	}
+
+	flagVisibilityMutex.RLock()
+	defer flagVisibilityMutex.RUnlock()
+
+	visibilityFunc, exists := flagVisibilityFunctions[flag]
+	return visibilityFunc, exists
+}
+
+// FlagValueIn returns a FlagVisibilityFunc showing a flag only when the value
+// of the named flag, given or default, is one of the given values.
+func FlagValueIn(flagName string, values ...string) FlagVisibilityFunc {
+	return func(cmd *Command) bool {
+		f := cmd.Flag(flagName)
+		return f != nil && stringInSlice(f.Value.String(), values)
+	}
+}
+
+// FlagChanged returns a FlagVisibilityFunc showing a flag only when the named flag is given.
+func FlagChanged(flagName string) FlagVisibilityFunc {
+	return func(cmd *Command) bool {
+		f := cmd.Flag(flagName)
+		return f != nil && f.Changed
+	}
+}
+
+// hideInapplicableFlags hides the flags of the command whose visibility
+// function returns false, and returns a function restoring them.
+func (c *Command) hideInapplicableFlags() func() {
+	c.mergePersistentFlags()
+
+	var hidden []*flag.Flag
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		if f.Hidden {
+			return
+		}
+		flagVisibilityMutex.RLock()
+		visible, exists := flagVisibilityFunctions[f]
+		flagVisibilityMutex.RUnlock()
+		if exists && !visible(c) {
+			hidden = append(hidden, f)
+		}
+	})
+	for _, f := range hidden {
+		f.Hidden = true
+	}
+	return func() {
+		for _, f := range hidden {
+			f.Hidden = false
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func newVisibilityTestCommands() (*Command, *Command) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().Bool("debug", false, "debug output")
+	deployCmd := &Command{Use: "deploy", Run: emptyRun}
+	deployCmd.Flags().String("target", "docker", "deployment target")
+	deployCmd.Flags().Int("replicas", 1, "number of replicas")
+	deployCmd.Flags().String("container-name", "", "name of the container")
+	deployCmd.Flags().String("log-level", "info", "log level")
+	assertNoErrPanic(deployCmd.RegisterFlagVisibilityFunc("replicas", FlagValueIn("target", "k8s")))
+	assertNoErrPanic(deployCmd.RegisterFlagVisibilityFunc("container-name", FlagValueIn("target", "docker")))
+	assertNoErrPanic(deployCmd.RegisterFlagVisibilityFunc("log-level", FlagChanged("debug")))
+	rootCmd.AddCommand(deployCmd)
+	return rootCmd, deployCmd
+}
+
+func assertNoErrPanic(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestFlagVisibilityInHelp(t *testing.T) {
+	rootCmd, _ := newVisibilityTestCommands()
+	output, err := executeCommand(rootCmd, "deploy", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--container-name")
+	checkStringOmits(t, output, "--replicas")
+	checkStringOmits(t, output, "--log-level")
+
+	rootCmd, deployCmd := newVisibilityTestCommands()
+	output, err = executeCommand(rootCmd, "deploy", "--target=k8s", "--debug", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--replicas")
+	checkStringContains(t, output, "--log-level")
+	checkStringOmits(t, output, "--container-name")
+
+	// The flags are restored after the help
+	if deployCmd.Flag("container-name").Hidden {
+		t.Error("Expected --container-name to be visible again")
+	}
+}
+
+func TestFlagVisibilityInCompletion(t *testing.T) {
+	rootCmd, _ := newVisibilityTestCommands()
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "deploy", "--target", "k8s", "--")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	completions := strings.Split(output, "\n")
+	for _, expected := range []string{"--replicas", "--debug"} {
+		if !stringInSlice(expected, completions) {
+			t.Errorf("Expected %s in the completions, got %q", expected, output)
+		}
+	}
+	for _, unexpected := range []string{"--container-name", "--log-level"} {
+		if stringInSlice(unexpected, completions) {
+			t.Errorf("Did not expect %s in the completions, got %q", unexpected, output)
+		}
+	}
+}
+
+func TestRegisterFlagVisibilityFunc(t *testing.T) {
+	_, deployCmd := newVisibilityTestCommands()
+	if err := deployCmd.RegisterFlagVisibilityFunc("missing", FlagChanged("target")); err == nil {
+		t.Error("Expected an error for a missing flag")
+	}
+	if err := deployCmd.RegisterFlagVisibilityFunc("replicas", FlagChanged("target")); err == nil {
+		t.Error("Expected an error for a flag already registered")
+	}
+	if _, exists := deployCmd.GetFlagVisibilityFunc("replicas"); !exists {
+		t.Error("Expected the visibility function of --replicas")
+	}
+}
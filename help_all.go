@@ -0,0 +1,178 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strconv"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	showHiddenFlagName = "help-all"
+
+	// flagRevealedAnnotation marks the hidden flags revealed by RevealHidden.
+	flagRevealedAnnotation = "cobra_annotation_flag_revealed"
+)
+
+// helpAllRequested returns whether the help should include hidden commands and flags:
+// EnableHelpAll must be set on the root command, and either the --help-all flag given
+// or the environment variable <PROGRAM>_HELP_ALL (or COBRA_HELP_ALL) set to true.
+func (c *Command) helpAllRequested() bool {
+	if !c.Root().EnableHelpAll {
+		return false
+	}
+	if c.helpAllRequestedByFlag() {
+		return true
+	}
+	helpAll, _ := strconv.ParseBool(getEnvConfig(c, "HELP_ALL"))
+	return helpAll
+}
+
+// helpAllRequestedByFlag returns whether --help-all was given on the command-line.
+func (c *Command) helpAllRequestedByFlag() bool {
+	f := c.Flags().Lookup(showHiddenFlagName)
+	return f != nil && f.Changed && f.Value.String() == "true"
+}
+
+// initHelpAllFlag adds the hidden --help-all flag to c if EnableHelpAll is set on the root command.
+func (c *Command) initHelpAllFlag() {
+	if !c.Root().EnableHelpAll {
+		return
+	}
+	c.mergePersistentFlags()
+	if c.Flags().Lookup(showHiddenFlagName) == nil {
+		c.Flags().Bool(showHiddenFlagName, false, Message(MsgHelpAllFlagUsageHidden))
+		_ = c.Flags().MarkHidden(showHiddenFlagName)
+		_ = c.Flags().SetAnnotation(showHiddenFlagName, FlagSetByCobraAnnotation, []string{"true"})
+	}
+}
+
+// RevealHidden makes the hidden commands and flags of the whole command tree
+// visible, with a marker after their description, until the returned function
+// is called. It is used for --help-all, and can be used around the doc generators:
+//
+//	defer rootCmd.RevealHidden()()
+//	err := doc.GenMarkdownTree(rootCmd, "/tmp/docs")
+func (c *Command) RevealHidden() func() {
+	var commands []*Command
+	var flags []*flag.Flag
+	seen := map[*flag.Flag]bool{}
+	revealFlag := func(f *flag.Flag) {
+		if !f.Hidden || seen[f] || f.Name == showHiddenFlagName {
+			return
+		}
+		seen[f] = true
+		flags = append(flags, f)
+	}
+
+	var walk func(cmd *Command)
+	walk = func(cmd *Command) {
+		if cmd.Name() == ShellCompRequestCmd || cmd.Name() == HelpJSONRequestCmd {
+			return
+		}
+		if cmd.Hidden {
+			commands = append(commands, cmd)
+		}
+		cmd.mergePersistentFlags()
+		cmd.Flags().VisitAll(revealFlag)
+		cmd.PersistentFlags().VisitAll(revealFlag)
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(c.Root())
+
+	for _, cmd := range commands {
+		cmd.Hidden = false
+		cmd.revealed = true
+	}
+	for _, f := range flags {
+		f.Hidden = false
+		if f.Annotations == nil {
+			f.Annotations = map[string][]string{}
+		}
+		f.Annotations[flagRevealedAnnotation] = []string{"true"}
+	}
+	return func() {
+		for _, cmd := range commands {
+			cmd.Hidden = true
+			cmd.revealed = false
+		}
+		for _, f := range flags {
+			f.Hidden = true
+			delete(f.Annotations, flagRevealedAnnotation)
+		}
+	}
+}
+
+// DisplayShort returns Short as shown by the help and the doc generators: in
+// the current locale and, for hidden commands revealed by RevealHidden, followed
+// by a marker.
+func (c *Command) DisplayShort() string {
+	return withHiddenMarker(c.LocalizedShort(), c.revealed)
+}
+
+// DisplayFlagUsage returns the usage of the given flag as shown by the help and
+// the doc generators: in the current locale and, for hidden flags revealed by
+// RevealHidden, followed by a marker.
+func (c *Command) DisplayFlagUsage(f *flag.Flag) string {
+	return withHiddenMarker(c.LocalizedFlagUsage(f), isRevealedFlag(f))
+}
+
+// DisplayFlags returns fs with the usage of its flags as returned by DisplayFlagUsage.
+// If the command has no TextProvider and no hidden flag is revealed, fs itself
+// is returned; otherwise a new FlagSet is built and fs is not modified.
+func (c *Command) DisplayFlags(fs *flag.FlagSet) *flag.FlagSet {
+	revealed := false
+	fs.VisitAll(func(f *flag.Flag) {
+		revealed = revealed || isRevealedFlag(f)
+	})
+	if c.TextProvider() == nil && !revealed {
+		return fs
+	}
+	out := c.flagsWithUsage(fs, c.DisplayFlagUsage)
+	out.VisitAll(func(f *flag.Flag) {
+		if !isRevealedFlag(f) {
+			return
+		}
+		// The marker is already part of the usage, f is a copy
+		annotations := make(map[string][]string, len(f.Annotations))
+		for k, v := range f.Annotations {
+			if k != flagRevealedAnnotation {
+				annotations[k] = v
+			}
+		}
+		f.Annotations = annotations
+	})
+	return out
+}
+
+// withHiddenMarker appends the marker of hidden commands and flags to s if revealed is true.
+func withHiddenMarker(s string, revealed bool) string {
+	if !revealed {
+		return s
+	}
+	if s == "" {
+		return Message(MsgHiddenMarker)
+	}
+	return s + " " + Message(MsgHiddenMarker)
+}
+
+// isRevealedFlag returns whether the flag is a hidden flag revealed by RevealHidden.
+func isRevealedFlag(f *flag.Flag) bool {
+	_, revealed := f.Annotations[flagRevealedAnnotation]
+	return revealed
+}
@@ -0,0 +1,146 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newHelpAllTestCommands(enable bool) *Command {
+	rootCmd := &Command{Use: "root", Run: emptyRun, EnableHelpAll: enable}
+	rootCmd.Flags().String("debug-addr", "", "address of the debug server")
+	_ = rootCmd.Flags().MarkHidden("debug-addr")
+	rootCmd.AddCommand(
+		&Command{Use: "visible", Short: "a visible command", Run: emptyRun},
+		&Command{Use: "secret", Short: "a secret command", Hidden: true, Run: emptyRun},
+	)
+	return rootCmd
+}
+
+func TestHelpAllFlag(t *testing.T) {
+	rootCmd := newHelpAllTestCommands(true)
+	output, err := executeCommand(rootCmd, "--help-all")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "secret      a secret command (hidden)")
+	checkStringContains(t, output, "--debug-addr string   address of the debug server (hidden)")
+	checkStringOmits(t, output, "help-all")
+
+	// Everything is hidden again afterwards
+	if secretCmd, _, _ := rootCmd.Find([]string{"secret"}); !secretCmd.Hidden || !rootCmd.Flags().Lookup("debug-addr").Hidden {
+		t.Error("Expected the hidden command and flag to be hidden again")
+	}
+	output, err = executeCommand(newHelpAllTestCommands(true), "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "secret")
+	checkStringOmits(t, output, "debug-addr")
+}
+
+func TestHelpAllFlagNotEnabled(t *testing.T) {
+	_, err := executeCommand(newHelpAllTestCommands(false), "--help-all")
+	if err == nil || !strings.Contains(err.Error(), "unknown flag: --help-all") {
+		t.Errorf("Expected an unknown flag error, got %v", err)
+	}
+}
+
+func TestHelpAllEnv(t *testing.T) {
+	setEnv(t, "ROOT_HELP_ALL", "true")
+
+	output, err := executeCommand(newHelpAllTestCommands(true), "help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "a secret command (hidden)")
+
+	output, err = executeCommand(newHelpAllTestCommands(false), "help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "secret")
+}
+
+func TestHelpAllCompletion(t *testing.T) {
+	output, err := executeCommand(newHelpAllTestCommands(true), ShellCompRequestCmd, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "secret")
+
+	setEnv(t, "ROOT_HELP_ALL", "1")
+	output, err = executeCommand(newHelpAllTestCommands(true), ShellCompRequestCmd, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "secret\ta secret command (hidden)")
+
+	output, err = executeCommand(newHelpAllTestCommands(true), ShellCompRequestCmd, "--d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--debug-addr\taddress of the debug server (hidden)")
+}
+
+func TestRevealHiddenLocalizedAccessors(t *testing.T) {
+	rootCmd := newHelpAllTestCommands(true)
+	secretCmd, _, _ := rootCmd.Find([]string{"secret"})
+	debugAddr := rootCmd.Flags().Lookup("debug-addr")
+
+	defer rootCmd.RevealHidden()()
+	if got := secretCmd.LocalizedShort(); got != "a secret command" {
+		t.Errorf("Expected LocalizedShort without marker, got %q", got)
+	}
+	if got := secretCmd.DisplayShort(); got != "a secret command (hidden)" {
+		t.Errorf("Expected DisplayShort with marker, got %q", got)
+	}
+	if got := rootCmd.LocalizedFlagUsage(debugAddr); got != "address of the debug server" {
+		t.Errorf("Expected LocalizedFlagUsage without marker, got %q", got)
+	}
+	if got := rootCmd.DisplayFlagUsage(debugAddr); got != "address of the debug server (hidden)" {
+		t.Errorf("Expected DisplayFlagUsage with marker, got %q", got)
+	}
+
+	info := rootCmd.HelpInfo()
+	for _, sub := range info.Subcommands {
+		checkStringOmits(t, sub.Short, "(hidden)")
+	}
+	for _, f := range info.Flags {
+		checkStringOmits(t, f.Usage, "(hidden)")
+	}
+}
+
+func TestRevealHiddenInheritedFlag(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	rootCmd.PersistentFlags().Bool("trace", false, "trace everything")
+	_ = rootCmd.PersistentFlags().MarkHidden("trace")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	restore := rootCmd.RevealHidden()
+	usage := childCmd.UsageString()
+	restore()
+
+	if n := strings.Count(usage, "(hidden)"); n != 1 {
+		t.Errorf("Expected exactly one marker, got %d in:\n%s", n, usage)
+	}
+	checkStringContains(t, usage, fmt.Sprintf("--trace   trace everything %s", Message(MsgHiddenMarker)))
+	if !rootCmd.PersistentFlags().Lookup("trace").Hidden {
+		t.Error("Expected the flag to be hidden again")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	// HelpJSONRequestCmd is the name of the hidden command printing the help
+	// of a command as JSON, e.g. "app __help-json sub".
+	HelpJSONRequestCmd = "__help-json"
+
+	// HelpJSONSchemaVersion is the version of the schema of HelpInfo. It is
+	// incremented whenever a change could break the readers of the JSON help.
+	HelpJSONSchemaVersion = 1
+)
+
+// HelpInfo describes the help of a command. It is written as JSON by
+// WriteHelpJSON and the hidden __help-json command, following the schema
+// of version HelpJSONSchemaVersion.
+type HelpInfo struct {
+	SchemaVersion  int              `json:"schemaVersion"`
+	Name           string           `json:"name"`
+	Path           string           `json:"path"`
+	UseLine        string           `json:"useLine"`
+	Synopsis       string           `json:"synopsis"`
+	Aliases        []string         `json:"aliases,omitempty"`
+	Short          string           `json:"short,omitempty"`
+	Long           string           `json:"long,omitempty"`
+	Example        string           `json:"example,omitempty"`
+	Examples       []ExampleInfo    `json:"examples,omitempty"`
+	Runnable       bool             `json:"runnable"`
+	Hidden         bool             `json:"hidden,omitempty"`
+	Deprecated     string           `json:"deprecated,omitempty"`
+	Args           *ArgsInfo        `json:"args,omitempty"`
+	Flags          []FlagInfo       `json:"flags,omitempty"`
+	InheritedFlags []FlagInfo       `json:"inheritedFlags,omitempty"`
+	FlagGroups     []FlagGroupInfo  `json:"flagGroups,omitempty"`
+	Groups         []GroupInfo      `json:"groups,omitempty"`
+	Subcommands    []SubcommandInfo `json:"subcommands,omitempty"`
+}
+
+// ArgsInfo describes the positional arguments of a command.
+type ArgsInfo struct {
+	Arguments  []ArgumentInfo `json:"arguments,omitempty"`
+	ValidArgs  []string       `json:"validArgs,omitempty"`
+	ArgAliases []string       `json:"argAliases,omitempty"`
+}
+
+// ArgumentInfo describes a positional argument set in Arguments.
+type ArgumentInfo struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// ExampleInfo describes a structured example set in Examples.
+type ExampleInfo struct {
+	Description string `json:"description,omitempty"`
+	CommandLine string `json:"commandLine"`
+	Output      string `json:"output,omitempty"`
+}
+
+// FlagInfo describes a flag.
+type FlagInfo struct {
+	Name         string `json:"name"`
+	Shorthand    string `json:"shorthand,omitempty"`
+	Type         string `json:"type"`
+	Default      string `json:"default"`
+	NoOptDefault string `json:"noOptDefault,omitempty"`
+	Usage        string `json:"usage"`
+	Long         string `json:"long,omitempty"`
+	Example      string `json:"example,omitempty"`
+	Required     bool   `json:"required,omitempty"`
+	Hidden       bool   `json:"hidden,omitempty"`
+	Deprecated   string `json:"deprecated,omitempty"`
+}
+
+// Kinds of flag groups in FlagGroupInfo.
+const (
+	FlagGroupRequiredTogether  = "requiredTogether"
+	FlagGroupOneRequired       = "oneRequired"
+	FlagGroupMutuallyExclusive = "mutuallyExclusive"
+)
+
+// FlagGroupInfo describes a group of flags created with MarkFlagsRequiredTogether,
+// MarkFlagsOneRequired or MarkFlagsMutuallyExclusive.
+type FlagGroupInfo struct {
+	Kind  string   `json:"kind"`
+	Flags []string `json:"flags"`
+}
+
+// GroupInfo describes a group of subcommands.
+type GroupInfo struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// SubcommandInfo describes a subcommand.
+type SubcommandInfo struct {
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Short      string   `json:"short,omitempty"`
+	GroupID    string   `json:"groupId,omitempty"`
+	Hidden     bool     `json:"hidden,omitempty"`
+	Deprecated string   `json:"deprecated,omitempty"`
+}
+
+// HelpInfo returns the description of the help of the command.
+func (c *Command) HelpInfo() *HelpInfo {
+	c.mergePersistentFlags()
+
+	info := &HelpInfo{
+		SchemaVersion: HelpJSONSchemaVersion,
+		Name:          c.Name(),
+		Path:          c.CommandPath(),
+		UseLine:       c.UseLine(),
+		Synopsis:      c.Synopsis(),
+		Aliases:       c.Aliases,
+		Short:         c.LocalizedShort(),
+		Long:          c.LocalizedLong(),
+		Example:       c.LocalizedExample(),
+		Runnable:      c.Runnable(),
+		Hidden:        c.Hidden,
+		Deprecated:    c.Deprecated,
+	}
+	for _, ex := range c.Examples {
+		info.Examples = append(info.Examples, ExampleInfo{Description: ex.Description, CommandLine: ex.CommandLine, Output: ex.Output})
+	}
+	if len(c.Arguments) > 0 || len(c.ValidArgs) > 0 || len(c.ArgAliases) > 0 {
+		info.Args = &ArgsInfo{ValidArgs: c.ValidArgs, ArgAliases: c.ArgAliases}
+		for _, arg := range c.Arguments {
+			info.Args.Arguments = append(info.Args.Arguments, ArgumentInfo{Name: arg.Name, Optional: arg.Optional, Variadic: arg.Variadic})
+		}
+	}
+	info.Flags = c.flagInfos(c.LocalFlags())
+	info.InheritedFlags = c.flagInfos(c.InheritedFlags())
+	info.FlagGroups = c.flagGroupInfos()
+	for _, g := range c.Groups() {
+		info.Groups = append(info.Groups, GroupInfo{ID: g.ID, Title: c.LocalizedGroupTitle(g)})
+	}
+	for _, sub := range c.Commands() {
+		if sub.Name() == ShellCompRequestCmd || sub.Name() == HelpJSONRequestCmd {
+			continue
+		}
+		info.Subcommands = append(info.Subcommands, SubcommandInfo{
+			Name:       sub.Name(),
+			Aliases:    sub.Aliases,
+			Short:      sub.LocalizedShort(),
+			GroupID:    sub.GroupID,
+			Hidden:     sub.Hidden,
+			Deprecated: sub.Deprecated,
+		})
+	}
+	return info
+}
+
+func (c *Command) flagInfos(flags *flag.FlagSet) []FlagInfo {
+	var infos []FlagInfo
+	flags.VisitAll(func(f *flag.Flag) {
+		info := FlagInfo{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      c.LocalizedFlagUsage(f),
+			Long:       c.LocalizedFlagLong(f),
+			Example:    c.LocalizedFlagExample(f),
+			Hidden:     f.Hidden,
+			Deprecated: f.Deprecated,
+		}
+		if f.NoOptDefVal != "" && f.Value.Type() != "bool" {
+			info.NoOptDefault = f.NoOptDefVal
+		}
+		info.Required = isRequiredFlag(f)
+		infos = append(infos, info)
+	})
+	return infos
+}
+
+func (c *Command) flagGroupInfos() []FlagGroupInfo {
+	var infos []FlagGroupInfo
+	for _, kind := range []struct {
+		name       string
+		annotation string
+	}{
+		{FlagGroupRequiredTogether, requiredAsGroupAnnotation},
+		{FlagGroupOneRequired, oneRequiredAnnotation},
+		{FlagGroupMutuallyExclusive, mutuallyExclusiveAnnotation},
+	} {
+		groups := map[string]bool{}
+		c.Flags().VisitAll(func(f *flag.Flag) {
+			for _, group := range f.Annotations[kind.annotation] {
+				groups[group] = true
+			}
+		})
+		names := make([]string, 0, len(groups))
+		for group := range groups {
+			names = append(names, group)
+		}
+		sort.Strings(names)
+		for _, group := range names {
+			infos = append(infos, FlagGroupInfo{Kind: kind.name, Flags: strings.Split(group, " ")})
+		}
+	}
+	return infos
+}
+
+// WriteHelpJSON writes the help of the command as JSON, see HelpInfo.
+func (c *Command) WriteHelpJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c.HelpInfo())
+}
+
+// initHelpJSONCmd adds the hidden __help-json command, if it is being called.
+func (c *Command) initHelpJSONCmd(args []string) {
+	helpJSONCmd := &Command{
+		Use:                   fmt.Sprintf("%s [command]", HelpJSONRequestCmd),
+		DisableFlagsInUseLine: true,
+		Hidden:                true,
+		DisableFlagParsing:    true,
+		Short:                 Message(MsgHelpJSONCmdShort),
+		RunE: func(cmd *Command, args []string) error {
+			target, _, err := cmd.Root().Find(args)
+			if err != nil {
+				return err
+			}
+			target.InitDefaultHelpFlag()
+			target.InitDefaultVersionFlag()
+			return target.WriteHelpJSON(cmd.OutOrStdout())
+		},
+	}
+	c.AddCommand(helpJSONCmd)
+	subCmd, _, err := c.Find(args)
+	if err != nil || subCmd != helpJSONCmd {
+		// Only create this special command if it is actually being called,
+		// as done for the __complete command.
+		c.RemoveCommand(helpJSONCmd)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHelpJSON(t *testing.T) {
+	rootCmd := &Command{Use: "root", Short: "the root"}
+	rootCmd.PersistentFlags().Bool("debug", false, "debug output")
+	rootCmd.AddGroup(&Group{ID: "manage", Title: "Management:"})
+	childCmd := &Command{
+		Use:       "child [name]",
+		Aliases:   []string{"kid"},
+		Short:     "the child",
+		Long:      "the child command",
+		Example:   "root child foo",
+		ValidArgs: []string{"foo", "bar"},
+		Arguments: []Argument{{Name: "NAME", Optional: true}},
+		Examples:  []Example{{Description: "Show foo", CommandLine: "root child foo", Output: "foo"}},
+		GroupID:   "manage",
+		Run:       emptyRun,
+	}
+	childCmd.Flags().StringP("output", "o", "text", "output format")
+	childCmd.Flags().Bool("json", false, "output JSON")
+	childCmd.Flags().Bool("yaml", false, "output YAML")
+	childCmd.Flags().String("secret", "", "secret flag")
+	_ = childCmd.Flags().MarkHidden("secret")
+	_ = childCmd.MarkFlagRequired("output")
+	childCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	rootCmd.AddCommand(childCmd, &Command{Use: "old", Deprecated: "do not use", Run: emptyRun})
+
+	output, err := executeCommand(rootCmd, HelpJSONRequestCmd, "child")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var info HelpInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", output, err)
+	}
+	if info.SchemaVersion != HelpJSONSchemaVersion {
+		t.Errorf("Unexpected schema version %d", info.SchemaVersion)
+	}
+	if info.Path != "root child" || info.UseLine != "root child [name] [flags]" || info.Aliases[0] != "kid" ||
+		info.Long != "the child command" || info.Example != "root child foo" || !info.Runnable {
+		t.Errorf("Unexpected description of the command: %+v", info)
+	}
+	if info.Synopsis != "root child --output string [--json | --yaml] [flags] [NAME]" {
+		t.Errorf("Unexpected synopsis %q", info.Synopsis)
+	}
+	if len(info.Examples) != 1 || info.Examples[0] != (ExampleInfo{Description: "Show foo", CommandLine: "root child foo", Output: "foo"}) {
+		t.Errorf("Unexpected examples: %+v", info.Examples)
+	}
+	if info.Args == nil || len(info.Args.ValidArgs) != 2 ||
+		len(info.Args.Arguments) != 1 || info.Args.Arguments[0] != (ArgumentInfo{Name: "NAME", Optional: true}) {
+		t.Errorf("Unexpected args: %+v", info.Args)
+	}
+
+	flags := map[string]FlagInfo{}
+	for _, f := range info.Flags {
+		flags[f.Name] = f
+	}
+	if f := flags["output"]; f.Shorthand != "o" || f.Type != "string" || f.Default != "text" || !f.Required {
+		t.Errorf("Unexpected description of --output: %+v", f)
+	}
+	if f := flags["secret"]; !f.Hidden {
+		t.Errorf("Unexpected description of --secret: %+v", f)
+	}
+	if _, found := flags["help"]; !found {
+		t.Errorf("Expected the help flag in %+v", info.Flags)
+	}
+	if len(info.InheritedFlags) != 1 || info.InheritedFlags[0].Name != "debug" || info.InheritedFlags[0].Type != "bool" {
+		t.Errorf("Unexpected inherited flags: %+v", info.InheritedFlags)
+	}
+	if len(info.FlagGroups) != 1 || info.FlagGroups[0].Kind != FlagGroupMutuallyExclusive || len(info.FlagGroups[0].Flags) != 2 {
+		t.Errorf("Unexpected flag groups: %+v", info.FlagGroups)
+	}
+
+	output, err = executeCommand(rootCmd, HelpJSONRequestCmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info = HelpInfo{}
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", output, err)
+	}
+	if len(info.Groups) != 1 || info.Groups[0].Title != "Management:" {
+		t.Errorf("Unexpected groups: %+v", info.Groups)
+	}
+	subcommands := map[string]SubcommandInfo{}
+	for _, sub := range info.Subcommands {
+		subcommands[sub.Name] = sub
+	}
+	if _, found := subcommands[HelpJSONRequestCmd]; found {
+		t.Errorf("Did not expect %s in the subcommands", HelpJSONRequestCmd)
+	}
+	if sub := subcommands["child"]; sub.GroupID != "manage" || sub.Short != "the child" {
+		t.Errorf("Unexpected description of child: %+v", sub)
+	}
+	if sub := subcommands["old"]; sub.Deprecated != "do not use" {
+		t.Errorf("Unexpected description of old: %+v", sub)
+	}
+}
+
+func TestHelpJSONUnknownCommand(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	_, err := executeCommand(rootCmd, HelpJSONRequestCmd, "unknown")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestHelpJSONCmdOnlyWhenCalled(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	if _, err := executeCommand(rootCmd); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if rootCmd.HasSubCommands() {
+		t.Errorf("Did not expect %s to be added", HelpJSONRequestCmd)
+	}
+}
+
+func TestHelpJSONCmdLocalized(t *testing.T) {
+	defer resetLocalization()
+
+	RegisterMessages("de", map[string]string{MsgHelpJSONCmdShort: "Die Hilfe als JSON ausgeben"})
+	SetLocale("de")
+
+	output, err := executeCommand(&Command{Use: "root", Run: emptyRun}, HelpJSONRequestCmd, HelpJSONRequestCmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var info HelpInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", output, err)
+	}
+	if info.Short != "Die Hilfe als JSON ausgeben" {
+		t.Errorf("Expected the localized short description, got %q", info.Short)
+	}
+}
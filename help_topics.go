@@ -0,0 +1,133 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HelpTopicAnnotation is the annotation of the help topic commands added by AddHelpTopics.
+// It holds the markdown source of the topic, without its title, for the doc generators.
+const HelpTopicAnnotation = "cobra_annotation_help_topic"
+
+var (
+	markdownHeadingRegexp  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownListItemRegexp = regexp.MustCompile(`^(\s*)([-*+]|\d+\.)\s+(.*)$`)
+	markdownLinkRegexp     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownStrongRegexp   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownEmRegexp       = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	markdownCodeRegexp     = regexp.MustCompile("`([^`]+)`")
+)
+
+// splitMarkdownTopic splits the markdown source of a help topic into its title,
+// the text of its first-level heading if it starts with one, and its body.
+func splitMarkdownTopic(src string) (title, body string) {
+	src = strings.TrimSpace(strings.ReplaceAll(src, "\r\n", "\n"))
+	firstLine := strings.SplitN(src, "\n", 2)[0]
+	if m := markdownHeadingRegexp.FindStringSubmatch(firstLine); m != nil && len(m[1]) == 1 {
+		return m[2], strings.TrimSpace(strings.TrimPrefix(src, firstLine))
+	}
+	return "", src
+}
+
+// renderMarkdown renders markdown as plain text for the terminal: the paragraphs are
+// joined in single lines to be wrapped by the help template, the headings end with a
+// colon as those of the help, the code blocks are indented and the inline markup is
+// removed, except for the targets of links.
+func renderMarkdown(src string) string {
+	var out []string
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			out = append(out, renderMarkdownInline(strings.Join(paragraph, " ")))
+			paragraph = nil
+		}
+	}
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+
+	fence := ""
+	inList := false
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				blank()
+			} else {
+				out = append(out, strings.TrimRight("    "+line, " \t"))
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			blank()
+			fence = trimmed[:3]
+		case trimmed == "":
+			flush()
+			blank()
+		case markdownHeadingRegexp.MatchString(line):
+			flush()
+			blank()
+			heading := renderMarkdownInline(markdownHeadingRegexp.FindStringSubmatch(line)[2])
+			if !strings.HasSuffix(heading, ":") && !strings.HasSuffix(heading, "?") {
+				heading += ":"
+			}
+			out = append(out, heading)
+		case markdownListItemRegexp.MatchString(line):
+			flush()
+			m := markdownListItemRegexp.FindStringSubmatch(line)
+			out = append(out, "  "+m[1]+m[2]+" "+renderMarkdownInline(m[3]))
+			inList = true
+			continue
+		case inList && (line[0] == ' ' || line[0] == '\t'):
+			// Continuation of a list item
+			out[len(out)-1] += " " + renderMarkdownInline(trimmed)
+			continue
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			flush()
+			out = append(out, strings.TrimRight(line, " \t"))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+		inList = false
+	}
+	flush()
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// renderMarkdownInline removes the inline markup of markdown.
+func renderMarkdownInline(s string) string {
+	s = markdownLinkRegexp.ReplaceAllString(s, "$1 ($2)")
+	s = markdownStrongRegexp.ReplaceAllString(s, "$1$2")
+	s = markdownEmRegexp.ReplaceAllString(s, "$1")
+	return markdownCodeRegexp.ReplaceAllString(s, "$1")
+}
+
+// newHelpTopicCommand creates the help topic command named name from its markdown source.
+func newHelpTopicCommand(name, src string) *Command {
+	title, body := splitMarkdownTopic(src)
+	return &Command{
+		Use:         name,
+		Short:       title,
+		Long:        renderMarkdown(body),
+		Annotations: map[string]string{HelpTopicAnnotation: body},
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package cobra
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// AddHelpTopics adds an additional help topic to c for each markdown file of the
+// directory dir of fsys, e.g. an embed.FS. The topic is named after the file, without
+// its .md extension, and its short description is the first-level heading the file
+// starts with, if any. The markdown is rendered as plain text for the help, e.g.
+// 'app help config-format', and kept as is by the doc generators.
+func (c *Command) AddHelpTopics(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".md")
+		for _, cmd := range c.commands {
+			if cmd.Name() == name || cmd.HasAlias(name) {
+				return fmt.Errorf("help topic %q conflicts with the command %q", name, cmd.CommandPath())
+			}
+		}
+		src, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		c.AddCommand(newHelpTopicCommand(name, string(src)))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func newHelpTopicsTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"topics/environment.md":   {Data: []byte("# Environment variables\n\nSet `APP_HOME` to change the **home** directory.\n")},
+		"topics/config-format.md": {Data: []byte("# Format of the config file\n\nThe config file is YAML.\n")},
+		"topics/README.txt":       {Data: []byte("not a topic")},
+		"topics/nested/other.md":  {Data: []byte("# Not added")},
+	}
+}
+
+func newHelpTopicsTestCommands(t *testing.T) *Command {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddCommand(&Command{Use: "run", Short: "run something", Run: emptyRun})
+	if err := rootCmd.AddHelpTopics(newHelpTopicsTestFS(), "topics"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return rootCmd
+}
+
+func TestAddHelpTopics(t *testing.T) {
+	output, err := executeCommand(newHelpTopicsTestCommands(t), "help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Additional help topics:\n"+
+		"  app config-format Format of the config file\n"+
+		"  app environment   Environment variables\n")
+	checkStringOmits(t, output, "other")
+	checkStringOmits(t, output, "README")
+
+	output, err = executeCommand(newHelpTopicsTestCommands(t), "help", "environment")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "Set APP_HOME to change the home directory.\n\n"; output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestAddHelpTopicsCompletion(t *testing.T) {
+	output, err := executeCommand(newHelpTopicsTestCommands(t), ShellCompRequestCmd, "help", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "config-format\tFormat of the config file\n")
+	checkStringContains(t, output, "environment\tEnvironment variables\n")
+	checkStringContains(t, output, "run\trun something\n")
+}
+
+func TestAddHelpTopicsErrors(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddCommand(&Command{Use: "environment", Run: emptyRun})
+	err := rootCmd.AddHelpTopics(newHelpTopicsTestFS(), "topics")
+	if err == nil || !strings.Contains(err.Error(), `help topic "environment" conflicts with the command "app environment"`) {
+		t.Errorf("Expected a conflict error, got %v", err)
+	}
+
+	if err := (&Command{Use: "app"}).AddHelpTopics(newHelpTopicsTestFS(), "missing"); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	src := "" +
+		"Cobra reads its **configuration** from a file,\n" +
+		"see [the docs](https://cobra.dev) and `app config`.\n" +
+		"\n" +
+		"## Variables\n" +
+		"\n" +
+		"- `APP_HOME`: the home\n" +
+		"  directory\n" +
+		"- `APP_DEBUG`: *enables* debugging\n" +
+		"\n" +
+		"```yaml\n" +
+		"# not a heading\n" +
+		"name: **value**\n" +
+		"```\n" +
+		"\n" +
+		"\n" +
+		"### Notes?\n" +
+		"Done.\n"
+	expected := "" +
+		"Cobra reads its configuration from a file, see the docs (https://cobra.dev) and app config.\n" +
+		"\n" +
+		"Variables:\n" +
+		"\n" +
+		"  - APP_HOME: the home directory\n" +
+		"  - APP_DEBUG: enables debugging\n" +
+		"\n" +
+		"    # not a heading\n" +
+		"    name: **value**\n" +
+		"\n" +
+		"Notes?\n" +
+		"Done."
+	if got := renderMarkdown(src); got != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
+
+func TestSplitMarkdownTopic(t *testing.T) {
+	title, body := splitMarkdownTopic("\n# Environment variables\n\nThe variables.\n")
+	if title != "Environment variables" || body != "The variables." {
+		t.Errorf("Unexpected title %q and body %q", title, body)
+	}
+	title, body = splitMarkdownTopic("## Not a title\nText")
+	if title != "" || body != "## Not a title\nText" {
+		t.Errorf("Unexpected title %q and body %q", title, body)
+	}
+}
@@ -0,0 +1,449 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	flag "github.com/spf13/pflag"
+)
+
+// DefaultLocale is the locale of the messages shipped with Cobra. It is used
+// whenever no translation is registered for the selected locale.
+const DefaultLocale = "en"
+
+// Identifiers of the messages generated by Cobra. Applications can provide
+// translations for them with RegisterMessages.
+// The values should not be changed: programs will be using them explicitly.
+const (
+	MsgUsage                  = "usage"
+	MsgAliases                = "aliases"
+	MsgExamples               = "examples"
+	MsgAvailableCommands      = "available_commands"
+	MsgAdditionalCommands     = "additional_commands"
+	MsgFlags                  = "flags"
+	MsgGlobalFlags            = "global_flags"
+	MsgAdditionalHelpTopics   = "additional_help_topics"
+	MsgMoreInformation        = "more_information"
+	MsgDidYouMean             = "did_you_mean"
+	MsgRunHelpForUsage        = "run_help_for_usage"
+	MsgErrorPrefix            = "error_prefix"
+	MsgCommandDeprecated      = "command_deprecated"
+	MsgHelpFlagUsage          = "help_flag_usage"
+	MsgHelpFlagUsageNoName    = "help_flag_usage_no_name"
+	MsgVersionFlagUsage       = "version_flag_usage"
+	MsgVersionFlagUsageNoName = "version_flag_usage_no_name"
+	MsgHelpCmdShort           = "help_cmd_short"
+	MsgHelpCmdLong            = "help_cmd_long"
+	MsgHelpJSONCmdShort       = "help_json_cmd_short"
+	MsgUnknownHelpTopic       = "unknown_help_topic"
+	MsgUnknownFlagHelpTopic   = "unknown_flag_help_topic"
+	MsgHelpSearchFlagUsage    = "help_search_flag_usage"
+	MsgHelpSearchNoMatch      = "help_search_no_match"
+	MsgHelpTreeFlagUsage      = "help_tree_flag_usage"
+	MsgHelpAllFlagUsage       = "help_all_flag_usage"
+	MsgHelpDepthFlagUsage     = "help_depth_flag_usage"
+	MsgHelpFlagsFlagUsage     = "help_flags_flag_usage"
+	MsgHelpAllFlagUsageHidden = "help_all_flag_usage_hidden"
+	MsgHiddenMarker           = "hidden_marker"
+	MsgCompletionCmdShort     = "completion_cmd_short"
+	MsgCompletionCmdLong      = "completion_cmd_long"
+	MsgCompletionShellShort   = "completion_shell_short"
+	MsgCompletionBashLong     = "completion_bash_long"
+	MsgCompletionZshLong      = "completion_zsh_long"
+	MsgCompletionFishLong     = "completion_fish_long"
+	MsgCompletionPwshLong     = "completion_powershell_long"
+	MsgCompletionNoDescFlag   = "completion_no_desc_flag"
+	MsgUnknownCommand         = "unknown_command"
+	MsgInvalidArgument        = "invalid_argument"
+	MsgInvalidFlagValue       = "invalid_flag_value"
+	MsgArgsAtLeast            = "args_at_least"
+	MsgArgsAtMost             = "args_at_most"
+	MsgArgsExactly            = "args_exactly"
+	MsgArgsBetween            = "args_between"
+	MsgRequiredFlagsNotSet    = "required_flags_not_set"
+	MsgFlagGroupRequired      = "flag_group_required"
+	MsgFlagGroupOneRequired   = "flag_group_one_required"
+	MsgFlagGroupExclusive     = "flag_group_exclusive"
+	MsgAutocorrectUnknown     = "autocorrect_unknown"
+	MsgAutocorrectPrompt      = "autocorrect_prompt"
+	MsgAutocorrectDelay       = "autocorrect_delay"
+	MsgAutocorrectNow         = "autocorrect_now"
+)
+
+// defaultMessages is the English catalog. Every message identifier must be present here.
+var defaultMessages = map[string]string{
+	MsgUsage:                  "Usage:",
+	MsgAliases:                "Aliases:",
+	MsgExamples:               "Examples:",
+	MsgAvailableCommands:      "Available Commands:",
+	MsgAdditionalCommands:     "Additional Commands:",
+	MsgFlags:                  "Flags:",
+	MsgGlobalFlags:            "Global Flags:",
+	MsgAdditionalHelpTopics:   "Additional help topics:",
+	MsgMoreInformation:        `Use "%s [command] --help" for more information about a command.`,
+	MsgDidYouMean:             "Did you mean this?",
+	MsgRunHelpForUsage:        "Run '%v --help' for usage.",
+	MsgErrorPrefix:            "Error:",
+	MsgCommandDeprecated:      "Command %q is deprecated, %s",
+	MsgHelpFlagUsage:          "help for %s",
+	MsgHelpFlagUsageNoName:    "help for this command",
+	MsgVersionFlagUsage:       "version for %s",
+	MsgVersionFlagUsageNoName: "version for this command",
+	MsgHelpCmdShort:           "Help about any command",
+	MsgHelpCmdLong: `Help provides help for any command in the application.
+Simply type %s help [path to command] for full details.`,
+	MsgHelpJSONCmdShort:       "Print the help of the specified command as JSON",
+	MsgUnknownHelpTopic:       "Unknown help topic %#q",
+	MsgUnknownFlagHelpTopic:   "unknown flag %q for %q",
+	MsgHelpSearchFlagUsage:    "search the names, descriptions, examples and flags of all commands",
+	MsgHelpSearchNoMatch:      "No command matches %q.",
+	MsgHelpTreeFlagUsage:      "print the tree of all the commands",
+	MsgHelpAllFlagUsage:       "same as --tree",
+	MsgHelpDepthFlagUsage:     "limit the number of levels of commands printed by --tree (0 for no limit)",
+	MsgHelpAllFlagUsageHidden: "help including the hidden commands and flags",
+	MsgHiddenMarker:           "(hidden)",
+	MsgHelpFlagsFlagUsage:     "include the flags of the commands printed by --tree",
+	MsgCompletionCmdShort:     "Generate the autocompletion script for the specified shell",
+	MsgCompletionCmdLong: `Generate the autocompletion script for %[1]s for the specified shell.
+See each sub-command's help for details on how to use the generated script.
+`,
+	MsgCompletionShellShort: "Generate the autocompletion script for %s",
+	MsgCompletionBashLong: `Generate the autocompletion script for the bash shell.
+
+This script depends on the 'bash-completion' package.
+If it is not installed already, you can install it via your OS's package manager.
+
+To load completions in your current shell session:
+
+	source <(%[1]s completion bash)
+
+To load completions for every new session, execute once:
+
+#### Linux:
+
+	%[1]s completion bash > /etc/bash_completion.d/%[1]s
+
+#### macOS:
+
+	%[1]s completion bash > $(brew --prefix)/etc/bash_completion.d/%[1]s
+
+You will need to start a new shell for this setup to take effect.
+`,
+	MsgCompletionZshLong: `Generate the autocompletion script for the zsh shell.
+
+If shell completion is not already enabled in your environment you will need
+to enable it.  You can execute the following once:
+
+	echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+To load completions in your current shell session:
+
+	source <(%[1]s completion zsh)
+
+To load completions for every new session, execute once:
+
+#### Linux:
+
+	%[1]s completion zsh > "${fpath[1]}/_%[1]s"
+
+#### macOS:
+
+	%[1]s completion zsh > $(brew --prefix)/share/zsh/site-functions/_%[1]s
+
+You will need to start a new shell for this setup to take effect.
+`,
+	MsgCompletionFishLong: `Generate the autocompletion script for the fish shell.
+
+To load completions in your current shell session:
+
+	%[1]s completion fish | source
+
+To load completions for every new session, execute once:
+
+	%[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+
+You will need to start a new shell for this setup to take effect.
+`,
+	MsgCompletionPwshLong: `Generate the autocompletion script for powershell.
+
+To load completions in your current shell session:
+
+	%[1]s completion powershell | Out-String | Invoke-Expression
+
+To load completions for every new session, add the output of the above command
+to your powershell profile.
+`,
+	MsgCompletionNoDescFlag: "disable completion descriptions",
+	MsgUnknownCommand:       "unknown command %q for %q",
+	MsgInvalidArgument:      "invalid argument %q for %q",
+	MsgInvalidFlagValue:     "invalid value %q for flag \"--%s\", valid values are: %s",
+	MsgArgsAtLeast:          "requires at least %d arg(s), only received %d",
+	MsgArgsAtMost:           "accepts at most %d arg(s), received %d",
+	MsgArgsExactly:          "accepts %d arg(s), received %d",
+	MsgArgsBetween:          "accepts between %d and %d arg(s), received %d",
+	MsgRequiredFlagsNotSet:  `required flag(s) "%s" not set`,
+	MsgFlagGroupRequired:    "if any flags in the group [%v] are set they must all be set; missing %v",
+	MsgFlagGroupOneRequired: "at least one of the flags in the group [%v] is required",
+	MsgFlagGroupExclusive:   "if any flags in the group [%v] are set none of the others can be; %v were all set",
+	MsgAutocorrectUnknown:   "WARNING: You called a %q command named %q, which does not exist.",
+	MsgAutocorrectPrompt:    "Run %q instead [y/N]? ",
+	MsgAutocorrectDelay:     "Continuing in %.1f seconds, assuming that you meant %q.",
+	MsgAutocorrectNow:       "Continuing under the assumption that you meant %q.",
+}
+
+var (
+	// catalogs holds the registered translations indexed by normalized locale.
+	catalogs = map[string]map[string]string{DefaultLocale: defaultMessages}
+	// selectedLocale is the locale set through SetLocale; empty means use the environment.
+	selectedLocale string
+	// lock for reading and writing catalogs and selectedLocale
+	catalogMutex = &sync.RWMutex{}
+)
+
+// RegisterMessages adds translations of Cobra's messages for the given locale.
+// The locale is a language tag such as "de" or "pt_BR"; messages is indexed by
+// the Msg* identifiers. Calling it several times for the same locale merges the
+// messages, later calls taking precedence. Messages that have no translation fall
+// back to the language-only locale and then to English.
+func RegisterMessages(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+
+	catalog, ok := catalogs[locale]
+	if !ok || locale == DefaultLocale {
+		// Never modify the shipped English catalog in place.
+		copied := make(map[string]string, len(catalog)+len(messages))
+		for k, v := range catalog {
+			copied[k] = v
+		}
+		catalog = copied
+		catalogs[locale] = catalog
+	}
+	for k, v := range messages {
+		catalog[k] = v
+	}
+}
+
+// SetLocale selects the locale used for Cobra's messages. An empty locale
+// restores the default behavior of selecting the locale from the LC_ALL,
+// LC_MESSAGES and LANG environment variables.
+func SetLocale(locale string) {
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	selectedLocale = normalizeLocale(locale)
+}
+
+// UseLocale selects the locale used for Cobra's messages, as SetLocale does,
+// until the returned function is called. The function restores the previous
+// selection, including the default of selecting the locale from the environment:
+//
+//	defer cobra.UseLocale("de")()
+func UseLocale(locale string) func() {
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	previous := selectedLocale
+	selectedLocale = normalizeLocale(locale)
+	return func() {
+		catalogMutex.Lock()
+		defer catalogMutex.Unlock()
+		selectedLocale = previous
+	}
+}
+
+// Locale returns the locale used for Cobra's messages: the one set with SetLocale
+// or, if none, the first of the LC_ALL, LC_MESSAGES and LANG environment variables
+// that is set. DefaultLocale is returned if none of them is set.
+func Locale() string {
+	catalogMutex.RLock()
+	locale := selectedLocale
+	catalogMutex.RUnlock()
+	if locale != "" {
+		return locale
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := normalizeLocale(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	return DefaultLocale
+}
+
+// Message returns the translation of the message identified by id for the
+// current locale. If there is no such message, id itself is returned.
+func Message(id string) string {
+	locale := Locale()
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+
+	for _, l := range []string{locale, localeLanguage(locale), DefaultLocale} {
+		if msg, ok := catalogs[l][id]; ok {
+			return msg
+		}
+	}
+	return id
+}
+
+// messagef formats the message identified by id for the current locale.
+func messagef(id string, a ...interface{}) string {
+	return fmt.Sprintf(Message(id), a...)
+}
+
+// normalizeLocale converts a POSIX locale such as "de_DE.UTF-8@euro" or a
+// language tag such as "de-DE" to the form used to index catalogs ("de_DE").
+// The "C" and "POSIX" locales are mapped to DefaultLocale.
+func normalizeLocale(locale string) string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "C" || locale == "POSIX" {
+		return DefaultLocale
+	}
+	return strings.ReplaceAll(locale, "-", "_")
+}
+
+// localeLanguage returns the language part of a normalized locale.
+func localeLanguage(locale string) string {
+	if i := strings.Index(locale, "_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// Fields of the command metadata that can be localized through a TextProvider.
+const (
+	TextShort       = "short"
+	TextLong        = "long"
+	TextExample     = "example"
+	TextGroupTitle  = "group_title"
+	TextFlagUsage   = "flag_usage"
+	TextFlagLong    = "flag_long"
+	TextFlagExample = "flag_example"
+)
+
+// TextKey identifies a piece of command metadata to be localized.
+type TextKey struct {
+	// CommandPath is the path of the command the metadata belongs to, as returned by
+	// CommandPath(). For flag usages, it is the path of the command being documented,
+	// which is not necessarily the command that defines the flag.
+	CommandPath string
+	// Field is one of TextShort, TextLong, TextExample, TextGroupTitle, TextFlagUsage,
+	// TextFlagLong or TextFlagExample.
+	Field string
+	// Name is the group ID for TextGroupTitle and the flag name for the flag fields.
+	// It is empty for the other fields.
+	Name string
+}
+
+// TextProvider resolves localized command metadata.
+type TextProvider interface {
+	// Text returns the text of key in the given locale, and false if there is none,
+	// in which case the value set on the Command, Group or Flag is used.
+	Text(locale string, key TextKey) (string, bool)
+}
+
+// TextProviderFunc is an adapter to allow the use of ordinary functions as a TextProvider.
+type TextProviderFunc func(locale string, key TextKey) (string, bool)
+
+// Text calls f(locale, key).
+func (f TextProviderFunc) Text(locale string, key TextKey) (string, bool) {
+	return f(locale, key)
+}
+
+// SetTextProvider sets the provider of localized metadata for this command and its children.
+func (c *Command) SetTextProvider(p TextProvider) {
+	c.textProvider = p
+}
+
+// TextProvider returns either the provider set by SetTextProvider for this command
+// or a parent, or nil if there is none.
+func (c *Command) TextProvider() TextProvider {
+	if c.textProvider != nil {
+		return c.textProvider
+	}
+	if c.HasParent() {
+		return c.parent.TextProvider()
+	}
+	return nil
+}
+
+// localizedText returns the text of key for the current locale, or def if the command
+// has no TextProvider or the provider has no text for key.
+func (c *Command) localizedText(key TextKey, def string) string {
+	p := c.TextProvider()
+	if p == nil {
+		return def
+	}
+	if text, ok := p.Text(Locale(), key); ok {
+		return text
+	}
+	return def
+}
+
+// LocalizedShort returns Short in the current locale.
+func (c *Command) LocalizedShort() string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextShort}, c.Short)
+}
+
+// LocalizedLong returns Long in the current locale.
+func (c *Command) LocalizedLong() string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextLong}, c.Long)
+}
+
+// LocalizedExample returns Example in the current locale.
+func (c *Command) LocalizedExample() string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextExample}, c.Example)
+}
+
+// LocalizedGroupTitle returns the title of the given group of subcommands in the current locale.
+func (c *Command) LocalizedGroupTitle(g *Group) string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextGroupTitle, Name: g.ID}, g.Title)
+}
+
+// LocalizedFlagUsage returns the usage of the given flag in the current locale.
+func (c *Command) LocalizedFlagUsage(f *flag.Flag) string {
+	return c.localizedText(TextKey{CommandPath: c.CommandPath(), Field: TextFlagUsage, Name: f.Name}, f.Usage)
+}
+
+// LocalizedFlags returns fs with the usage of its flags in the current locale.
+// If the command has no TextProvider, fs itself is returned; otherwise a new
+// FlagSet is built and fs is not modified.
+func (c *Command) LocalizedFlags(fs *flag.FlagSet) *flag.FlagSet {
+	if c.TextProvider() == nil {
+		return fs
+	}
+	return c.flagsWithUsage(fs, c.LocalizedFlagUsage)
+}
+
+// flagsWithUsage returns a new FlagSet with the flags of fs, using the given usage.
+func (c *Command) flagsWithUsage(fs *flag.FlagSet, usage func(*flag.Flag) string) *flag.FlagSet {
+	out := flag.NewFlagSet(c.DisplayName(), flag.ContinueOnError)
+	out.SortFlags = fs.SortFlags
+	fs.VisitAll(func(f *flag.Flag) {
+		u := usage(f)
+		if u == f.Usage {
+			out.AddFlag(f)
+			return
+		}
+		localized := *f
+		localized.Usage = u
+		out.AddFlag(&localized)
+	})
+	return out
+}
@@ -0,0 +1,274 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"testing"
+)
+
+func resetLocalization() {
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	catalogs = map[string]map[string]string{DefaultLocale: defaultMessages}
+	selectedLocale = ""
+}
+
+func TestLocaleFromEnvironment(t *testing.T) {
+	defer resetLocalization()
+
+	testcases := []struct {
+		lcAll, lcMessages, lang string
+		expected                string
+	}{
+		{"", "", "", DefaultLocale},
+		{"", "", "de_DE.UTF-8", "de_DE"},
+		{"", "fr_CA", "de_DE.UTF-8", "fr_CA"},
+		{"pt-BR", "fr_CA", "de_DE.UTF-8", "pt_BR"},
+		{"C", "", "de_DE", DefaultLocale},
+		{"", "", "sr_RS@latin", "sr_RS"},
+	}
+	for _, tc := range testcases {
+		t.Setenv("LC_ALL", tc.lcAll)
+		t.Setenv("LC_MESSAGES", tc.lcMessages)
+		t.Setenv("LANG", tc.lang)
+		if got := Locale(); got != tc.expected {
+			t.Errorf("LC_ALL=%q LC_MESSAGES=%q LANG=%q: expected locale %q, got %q", tc.lcAll, tc.lcMessages, tc.lang, tc.expected, got)
+		}
+	}
+
+	SetLocale("it")
+	if got := Locale(); got != "it" {
+		t.Errorf("Expected locale set through SetLocale to take precedence, got %q", got)
+	}
+}
+
+func TestUseLocale(t *testing.T) {
+	defer resetLocalization()
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+
+	restore := UseLocale("de")
+	if got := Locale(); got != "de" {
+		t.Errorf("Expected locale %q, got %q", "de", got)
+	}
+	restore()
+	if got := Locale(); got != "fr_FR" {
+		t.Errorf("Expected the environment to select the locale again, got %q", got)
+	}
+
+	SetLocale("it")
+	UseLocale("de")()
+	if got := Locale(); got != "it" {
+		t.Errorf("Expected locale %q to be restored, got %q", "it", got)
+	}
+}
+
+func TestMessageFallback(t *testing.T) {
+	defer resetLocalization()
+
+	RegisterMessages("de", map[string]string{MsgUsage: "Verwendung:", MsgFlags: "Optionen:"})
+	RegisterMessages("de_AT", map[string]string{MsgFlags: "Schalter:"})
+
+	SetLocale("de_AT.UTF-8")
+	if got := Message(MsgFlags); got != "Schalter:" {
+		t.Errorf("Expected the regional translation, got %q", got)
+	}
+	if got := Message(MsgUsage); got != "Verwendung:" {
+		t.Errorf("Expected the language translation, got %q", got)
+	}
+	if got := Message(MsgGlobalFlags); got != "Global Flags:" {
+		t.Errorf("Expected the English message, got %q", got)
+	}
+	if got := Message("no_such_message"); got != "no_such_message" {
+		t.Errorf("Expected the identifier for an unknown message, got %q", got)
+	}
+
+	SetLocale("")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	if got := Message(MsgUsage); got != "Usage:" {
+		t.Errorf("Expected the English message after resetting the locale, got %q", got)
+	}
+}
+
+func TestRegisterMessagesDoesNotModifyDefaultCatalog(t *testing.T) {
+	defer resetLocalization()
+
+	RegisterMessages(DefaultLocale, map[string]string{MsgUsage: "Synopsis:"})
+	SetLocale(DefaultLocale)
+	if got := Message(MsgUsage); got != "Synopsis:" {
+		t.Errorf("Expected the overridden English message, got %q", got)
+	}
+	if defaultMessages[MsgUsage] != "Usage:" {
+		t.Errorf("The shipped English catalog must not be modified")
+	}
+}
+
+func TestLocalizedHelpAndErrors(t *testing.T) {
+	defer resetLocalization()
+
+	RegisterMessages("de", map[string]string{
+		MsgUsage:           "Verwendung:",
+		MsgFlags:           "Optionen:",
+		MsgHelpFlagUsage:   "Hilfe für %s",
+		MsgArgsExactly:     "erwartet %d Argument(e), %d erhalten",
+		MsgErrorPrefix:     "Fehler:",
+		MsgRunHelpForUsage: "Siehe '%v --help'.",
+	})
+	SetLocale("de")
+
+	rootCmd := &Command{Use: "root", Args: ExactArgs(1), Run: emptyRun}
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Verwendung:\n  root [flags]")
+	checkStringContains(t, output, "Optionen:\n")
+	checkStringContains(t, output, "Hilfe für root")
+
+	rootCmd = &Command{Use: "root", Args: ExactArgs(1), Run: emptyRun}
+	output, err = executeCommand(rootCmd)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringContains(t, output, "Fehler: erwartet 1 Argument(e), 0 erhalten")
+}
+
+// TestDefaultUsageFuncMatchesTemplate makes sure defaultUsageFunc and
+// defaultUsageTemplate stay in sync, including for translated messages.
+func TestDefaultUsageFuncMatchesTemplate(t *testing.T) {
+	defer resetLocalization()
+
+	RegisterMessages("fr", map[string]string{
+		MsgUsage:                "Utilisation :",
+		MsgAliases:              "Alias :",
+		MsgExamples:             "Exemples :",
+		MsgAvailableCommands:    "Commandes disponibles :",
+		MsgAdditionalCommands:   "Commandes supplémentaires :",
+		MsgFlags:                "Options :",
+		MsgGlobalFlags:          "Options globales :",
+		MsgAdditionalHelpTopics: "Autres rubriques d'aide :",
+		MsgMoreInformation:      `Utilisez "%s [command] --help" pour plus d'informations.`,
+	})
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().String("global", "", "a global flag")
+	rootCmd.AddGroup(&Group{ID: "group", Title: "Grouped:"})
+	childCmd := &Command{Use: "child", Aliases: []string{"kid"}, Example: "root child", Short: "a child", GroupID: "group", Run: emptyRun}
+	childCmd.Flags().Bool("local", false, "a local flag")
+	childCmd.AddCommand(&Command{Use: "grandchild", Run: emptyRun}, &Command{Use: "topic", Short: "a help topic"})
+	rootCmd.AddCommand(childCmd, &Command{Use: "other", Short: "other command", Run: emptyRun})
+	rootCmd.InitDefaultHelpCmd()
+
+	for _, locale := range []string{DefaultLocale, "fr"} {
+		SetLocale(locale)
+		for _, cmd := range []*Command{rootCmd, childCmd} {
+			cmd.InitDefaultHelpFlag()
+			cmd.mergePersistentFlags()
+
+			funcBuf := new(bytes.Buffer)
+			if err := defaultUsageFunc(funcBuf, cmd); err != nil {
+				t.Fatal(err)
+			}
+			tmplBuf := new(bytes.Buffer)
+			if err := tmpl(defaultUsageTemplate).fn(tmplBuf, cmd); err != nil {
+				t.Fatal(err)
+			}
+			if funcBuf.String() != tmplBuf.String() {
+				t.Errorf("Locale %q, command %q: usage function and template differ.\nFunction:\n%s\nTemplate:\n%s",
+					locale, cmd.Name(), funcBuf.String(), tmplBuf.String())
+			}
+		}
+	}
+}
+
+func germanTextProvider() TextProvider {
+	texts := map[TextKey]string{
+		{CommandPath: "root child", Field: TextShort}:                         "ein Kind",
+		{CommandPath: "root child", Field: TextLong}:                          "ein ausführliches Kind",
+		{CommandPath: "root child", Field: TextExample}:                       "root child --laut",
+		{CommandPath: "root", Field: TextGroupTitle, Name: "main"}:            "Hauptbefehle:",
+		{CommandPath: "root child", Field: TextFlagUsage, Name: "loud"}:       "laute Ausgabe",
+		{CommandPath: "root child", Field: TextFlagUsage, Name: "persistent"}: "globale Option",
+	}
+	return TextProviderFunc(func(locale string, key TextKey) (string, bool) {
+		if locale != "de" {
+			return "", false
+		}
+		text, ok := texts[key]
+		return text, ok
+	})
+}
+
+func TestTextProvider(t *testing.T) {
+	defer resetLocalization()
+
+	newCommands := func() (*Command, *Command) {
+		rootCmd := &Command{Use: "root", Run: emptyRun}
+		rootCmd.PersistentFlags().Bool("persistent", false, "persistent flag")
+		rootCmd.AddGroup(&Group{ID: "main", Title: "Main commands:"})
+		childCmd := &Command{Use: "child", Short: "a child", Long: "a verbose child", Example: "root child --loud", GroupID: "main", Run: emptyRun}
+		childCmd.Flags().Bool("loud", false, "loud output")
+		rootCmd.AddCommand(childCmd)
+		rootCmd.SetTextProvider(germanTextProvider())
+		return rootCmd, childCmd
+	}
+
+	SetLocale("de")
+	rootCmd, _ := newCommands()
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Hauptbefehle:\n  child       ein Kind")
+
+	rootCmd, _ = newCommands()
+	output, err = executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "ein ausführliches Kind")
+	checkStringContains(t, output, "root child --laut")
+	checkStringContains(t, output, "laute Ausgabe")
+	checkStringContains(t, output, "globale Option")
+	checkStringOmits(t, output, "a verbose child")
+
+	rootCmd, _ = newCommands()
+	output, err = executeCommand(rootCmd, ShellCompRequestCmd, "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "child\tein Kind")
+
+	rootCmd, childCmd := newCommands()
+	output, err = executeCommand(rootCmd, ShellCompRequestCmd, "child", "--l")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--loud\tlaute Ausgabe")
+
+	// Without a translation for the locale, the metadata of the command is used
+	SetLocale("ja")
+	if got := childCmd.LocalizedShort(); got != childCmd.Short {
+		t.Errorf("Expected %q, got %q", childCmd.Short, got)
+	}
+	if got := childCmd.LocalizedFlags(childCmd.LocalFlags()).Lookup("loud").Usage; got != "loud output" {
+		t.Errorf("Expected the original flag usage, got %q", got)
+	}
+	if got := childCmd.LocalFlags().Lookup("loud").Usage; got != "loud output" {
+		t.Errorf("The original flag must not be modified, got %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	defaultPager = "less"
+	// The options set in the LESS environment variable when it is not set:
+	// exit if the output fits on one screen, keep colors and don't clear the screen.
+	defaultLessOptions = "FRX"
+)
+
+// PagerOptions are the options to control the use of a pager for long output.
+// The pager is only used when the standard output is a terminal.
+//
+// The pager command is, in order of precedence, the value of the environment
+// variable <PROGRAM>_PAGER, Command, the value of PAGER, or "less".
+// Setting <PROGRAM>_PAGER to "" or "cat", or setting NO_PAGER or
+// <PROGRAM>_NO_PAGER to a non-empty value, disables the pager.
+type PagerOptions struct {
+	// Help pipes the help through the pager.
+	Help bool
+	// Output pipes what the executed command writes to OutOrStdout through the pager.
+	Output bool
+	// Command is the pager command line, with its arguments separated by spaces.
+	Command string
+}
+
+// pagerCommand returns the pager command line to use, or "" if the pager is disabled.
+func (c *Command) pagerCommand() string {
+	root := c.Root()
+	if os.Getenv("NO_PAGER") != "" || os.Getenv(configEnvVar(root.Name(), "NO_PAGER")) != "" {
+		return ""
+	}
+
+	pager, found := os.LookupEnv(configEnvVar(root.Name(), "PAGER"))
+	if !found {
+		pager = root.PagerOptions.Command
+		if pager == "" {
+			pager = os.Getenv("PAGER")
+		}
+		if pager == "" {
+			pager = defaultPager
+		}
+	}
+	pager = strings.TrimSpace(pager)
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// startPager starts the pager for the standard output of c if enabled is true
+// and the pager is not disabled. It returns the writer the output should be
+// written to, and a function which must be called once everything has been
+// written, or nil if the output should be written directly to OutOrStdout.
+// If the pager cannot be started, nil is returned and the output is not paged.
+func (c *Command) startPager(enabled bool) (io.Writer, func()) {
+	if !enabled {
+		return nil, nil
+	}
+	out := c.OutOrStdout()
+	if !isTerminal(out) {
+		return nil, nil
+	}
+	args := strings.Fields(c.pagerCommand())
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	pager := exec.Command(args[0], args[1:]...) // #nosec G204
+	pager.Stdout = out
+	pager.Stderr = c.ErrOrStderr()
+	if _, found := os.LookupEnv("LESS"); !found {
+		pager.Env = append(os.Environ(), "LESS="+defaultLessOptions)
+	}
+	in, err := pager.StdinPipe()
+	if err != nil {
+		return nil, nil
+	}
+	if err := pager.Start(); err != nil {
+		// Most likely the pager is not installed
+		return nil, nil
+	}
+	return in, func() {
+		_ = in.Close()
+		_ = pager.Wait()
+	}
+}
+
+// withPager runs fn with the standard output of c piped through the pager,
+// if enabled is true and the pager is available.
+func (c *Command) withPager(enabled bool, fn func()) {
+	w, done := c.startPager(enabled)
+	if done == nil {
+		fn()
+		return
+	}
+
+	tmpOutput := c.outWriter
+	tmpHelpOutput := c.helpOutput
+	if c.helpOutput == nil {
+		c.helpOutput = c.OutOrStdout()
+	}
+	c.outWriter = w
+	defer func() {
+		c.outWriter = tmpOutput
+		c.helpOutput = tmpHelpOutput
+		done()
+	}()
+	fn()
+}
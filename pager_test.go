@@ -0,0 +1,180 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestPagerHelperProcess is not a real test: it is the fake pager used by the
+// tests below, which prefixes every line it reads with "PAGED: ".
+func TestPagerHelperProcess(t *testing.T) {
+	if os.Getenv("COBRA_TEST_FAKE_PAGER") != "1" {
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fmt.Println("PAGED: " + scanner.Text())
+	}
+	os.Exit(0)
+}
+
+// setEnv sets an environment variable for the duration of the test.
+// It is like t.Setenv, which requires Go 1.17.
+func setEnv(t *testing.T, key, value string) {
+	orig, found := os.LookupEnv(key)
+	assertNoErr(t, os.Setenv(key, value))
+	t.Cleanup(func() {
+		if found {
+			_ = os.Setenv(key, orig)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
+
+// unsetEnv unsets an environment variable for the duration of the test.
+func unsetEnv(t *testing.T, key string) {
+	setEnv(t, key, "")
+	assertNoErr(t, os.Unsetenv(key))
+}
+
+func fakePager() string {
+	return os.Args[0] + " -test.run=TestPagerHelperProcess"
+}
+
+func setupFakePager(t *testing.T) {
+	setTerminal(t, true)
+	setEnv(t, "COBRA_TEST_FAKE_PAGER", "1")
+	setEnv(t, "PAGER", "")
+	setEnv(t, "NO_PAGER", "")
+	setEnv(t, "ROOT_PAGER", fakePager())
+	setEnv(t, "ROOT_NO_PAGER", "")
+}
+
+func newPagerTestCommands() (*Command, *Command) {
+	rootCmd := &Command{Use: "root", Short: "the root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Short: "the child", Run: func(cmd *Command, args []string) {
+		cmd.Println("child output")
+	}}
+	rootCmd.AddCommand(childCmd)
+	return rootCmd, childCmd
+}
+
+func TestPagerHelp(t *testing.T) {
+	setupFakePager(t)
+
+	rootCmd, _ := newPagerTestCommands()
+	rootCmd.PagerOptions.Help = true
+	output, err := executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "PAGED: the child\n")
+	checkStringContains(t, output, "PAGED: Usage:\n")
+
+	rootCmd, _ = newPagerTestCommands()
+	rootCmd.PagerOptions.Help = true
+	output, err = executeCommand(rootCmd, "help", "child")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "PAGED: the child\n")
+
+	// The output of the command is not paged
+	rootCmd, _ = newPagerTestCommands()
+	rootCmd.PagerOptions.Help = true
+	output, err = executeCommand(rootCmd, "child")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "child output\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+}
+
+func TestPagerOutput(t *testing.T) {
+	setupFakePager(t)
+
+	rootCmd, _ := newPagerTestCommands()
+	rootCmd.PagerOptions.Output = true
+	output, err := executeCommand(rootCmd, "child")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "PAGED: child output\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	// Completions are never paged
+	rootCmd, _ = newPagerTestCommands()
+	rootCmd.PagerOptions.Output = true
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "ch")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "PAGED")
+}
+
+func TestPagerConfiguredCommand(t *testing.T) {
+	setupFakePager(t)
+	unsetEnv(t, "ROOT_PAGER")
+	setEnv(t, "PAGER", "missing-pager-for-cobra-tests")
+
+	rootCmd, _ := newPagerTestCommands()
+	rootCmd.PagerOptions = PagerOptions{Help: true, Command: fakePager()}
+	output, err := executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "PAGED: the child\n")
+}
+
+func TestPagerDisabled(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		terminal bool
+		env      map[string]string
+	}{
+		{"not a terminal", false, nil},
+		{"NO_PAGER", true, map[string]string{"NO_PAGER": "1"}},
+		{"ROOT_NO_PAGER", true, map[string]string{"ROOT_NO_PAGER": "1"}},
+		{"empty ROOT_PAGER", true, map[string]string{"ROOT_PAGER": ""}},
+		{"cat", true, map[string]string{"ROOT_PAGER": "cat"}},
+		{"missing pager", true, map[string]string{"ROOT_PAGER": "missing-pager-for-cobra-tests"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			setupFakePager(t)
+			setTerminal(t, tc.terminal)
+			for k, v := range tc.env {
+				setEnv(t, k, v)
+			}
+
+			rootCmd, _ := newPagerTestCommands()
+			rootCmd.PagerOptions = PagerOptions{Help: true, Output: true}
+			output, err := executeCommand(rootCmd, "child", "--help")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			checkStringOmits(t, output, "PAGED")
+			checkStringContains(t, output, "the child\n")
+		})
+	}
+}
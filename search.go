@@ -0,0 +1,160 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+const helpSearchFlagName = "search"
+
+// Weights of the fields of a command when ranking search results.
+const (
+	searchScoreName      = 100
+	searchScoreNamePart  = 50
+	searchScoreAlias     = 80
+	searchScoreAliasPart = 40
+	searchScoreShort     = 20
+	searchScoreLong      = 10
+	searchScoreExample   = 5
+	searchScoreFlag      = 5
+)
+
+// SearchResult is a command matching a search, as returned by SearchCommands.
+type SearchResult struct {
+	// Command is the matching command.
+	Command *Command
+	// Score ranks the results: the higher the better.
+	Score int
+}
+
+// SearchCommands searches the command and its descendants for the given term,
+// in their names, aliases, Short, Long, Example and flag usages, ignoring case.
+// When the term contains several words, all of them must be found.
+// Hidden and deprecated commands and flags are ignored.
+// The results are sorted by decreasing score, then by command path.
+func (c *Command) SearchCommands(term string) []SearchResult {
+	words := strings.Fields(strings.ToLower(term))
+	if len(words) == 0 {
+		return nil
+	}
+
+	var results []SearchResult
+	var search func(cmd *Command)
+	search = func(cmd *Command) {
+		if cmd.Hidden || len(cmd.Deprecated) > 0 {
+			return
+		}
+		if score := cmd.searchScore(words); score > 0 {
+			results = append(results, SearchResult{Command: cmd, Score: score})
+		}
+		for _, sub := range cmd.Commands() {
+			search(sub)
+		}
+	}
+	search(c)
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Command.CommandPath() < results[j].Command.CommandPath()
+	})
+	return results
+}
+
+// searchScore returns the score of the command for the given lower case
+// words, or 0 if any of them is not found.
+func (c *Command) searchScore(words []string) int {
+	name := strings.ToLower(c.Name())
+	short := strings.ToLower(c.DisplayShort())
+	long := strings.ToLower(c.LocalizedLong())
+	example := strings.ToLower(c.DisplayExample())
+	var flagTexts []string
+	c.LocalFlags().VisitAll(func(f *flag.Flag) {
+		if f.Hidden || len(f.Deprecated) > 0 {
+			return
+		}
+		flagTexts = append(flagTexts, strings.ToLower(f.Name+" "+c.DisplayFlagUsage(f)))
+	})
+
+	total := 0
+	for _, word := range words {
+		score := 0
+		switch {
+		case name == word:
+			score += searchScoreName
+		case strings.Contains(name, word):
+			score += searchScoreNamePart
+		}
+		for _, alias := range c.Aliases {
+			alias = strings.ToLower(alias)
+			if alias == word {
+				score += searchScoreAlias
+				break
+			} else if strings.Contains(alias, word) {
+				score += searchScoreAliasPart
+				break
+			}
+		}
+		if strings.Contains(short, word) {
+			score += searchScoreShort
+		}
+		if strings.Contains(long, word) {
+			score += searchScoreLong
+		}
+		if strings.Contains(example, word) {
+			score += searchScoreExample
+		}
+		for _, text := range flagTexts {
+			if strings.Contains(text, word) {
+				score += searchScoreFlag
+				break
+			}
+		}
+		if score == 0 {
+			return 0
+		}
+		total += score
+	}
+	return total
+}
+
+// printSearchResults prints the command paths and short descriptions of the
+// commands matching term, as the help command does with --search.
+func (c *Command) printSearchResults(w io.Writer, term string) {
+	results := c.SearchCommands(term)
+	if len(results) == 0 {
+		fmt.Fprintln(w, messagef(MsgHelpSearchNoMatch, term))
+		return
+	}
+
+	padding := 0
+	for _, r := range results {
+		if l := len(r.Command.CommandPath()); l > padding {
+			padding = l
+		}
+	}
+	width := c.HelpWidth()
+	for _, r := range results {
+		path := rpad(r.Command.CommandPath(), padding)
+		fmt.Fprintf(w, "  %s %s\n", c.StyleCommand(path), wrapIndent(len(path)+3, width, r.Command.DisplayShort()))
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"testing"
+)
+
+func newSearchTestCommands() *Command {
+	rootCmd := &Command{Use: "root", Short: "the root command"}
+	remoteCmd := &Command{Use: "remote", Short: "manage remote repositories"}
+	remoteCmd.AddCommand(
+		&Command{Use: "add", Short: "add a remote", Run: emptyRun},
+		&Command{Use: "prune", Short: "remove stale branches of a remote", Run: emptyRun},
+	)
+	cloneCmd := &Command{
+		Use:     "clone",
+		Aliases: []string{"copy"},
+		Short:   "clone a repository",
+		Long:    "Clone a repository into a new directory, fetching from the remote.",
+		Example: "root clone https://example.com/repo.git",
+		Run:     emptyRun,
+	}
+	cloneCmd.Flags().Int("depth", 0, "create a shallow clone with a history truncated to the given number of commits")
+	cloneCmd.Flags().String("mirror-remote", "", "hidden flag")
+	_ = cloneCmd.Flags().MarkHidden("mirror-remote")
+	rootCmd.AddCommand(
+		remoteCmd,
+		cloneCmd,
+		&Command{Use: "secret", Short: "a remote secret", Hidden: true, Run: emptyRun},
+		&Command{Use: "old", Short: "an old remote command", Deprecated: "use remote", Run: emptyRun},
+	)
+	return rootCmd
+}
+
+func searchResultPaths(results []SearchResult) []string {
+	var paths []string
+	for _, r := range results {
+		paths = append(paths, r.Command.CommandPath())
+	}
+	return paths
+}
+
+func TestSearchCommands(t *testing.T) {
+	testCases := []struct {
+		term     string
+		expected []string
+	}{
+		{"remote", []string{"root remote", "root remote add", "root remote prune", "root clone"}},
+		{"REMOTE add", []string{"root remote add"}},
+		{"copy", []string{"root clone"}},
+		{"shallow", []string{"root clone"}},
+		{"example.com", []string{"root clone"}},
+		{"secret", nil},
+		{"mirror", nil},
+		{"", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.term, func(t *testing.T) {
+			got := searchResultPaths(newSearchTestCommands().SearchCommands(tc.term))
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("expected %v, got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpSearch(t *testing.T) {
+	output, err := executeCommand(newSearchTestCommands(), "help", "--search", "remote")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := "" +
+		"  root remote       manage remote repositories\n" +
+		"  root remote add   add a remote\n" +
+		"  root remote prune remove stale branches of a remote\n" +
+		"  root clone        clone a repository\n"
+	if output != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, output)
+	}
+
+	output, err = executeCommand(newSearchTestCommands(), "help", "-k", "remote", "add")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "  root remote add add a remote\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	output, err = executeCommand(newSearchTestCommands(), "help", "-k", "nothing")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "No command matches \"nothing\".\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+}
@@ -78,6 +78,27 @@ func MarkFlagCustom(flags *pflag.FlagSet, name string, f string) error {
 	return flags.SetAnnotation(name, BashCompCustom, []string{f})
 }
 
+// MarkFlagValidValues restricts the named flag to the given values: the
+// various shell completion implementations complete these values, and your
+// command reports an error, with suggestions, if invoked with any other value.
+func (c *Command) MarkFlagValidValues(name string, values ...string) error {
+	return MarkFlagValidValues(c.Flags(), name, values...)
+}
+
+// MarkPersistentFlagValidValues restricts the named persistent flag to the given
+// values: the various shell completion implementations complete these values, and
+// your command reports an error, with suggestions, if invoked with any other value.
+func (c *Command) MarkPersistentFlagValidValues(name string, values ...string) error {
+	return MarkFlagValidValues(c.PersistentFlags(), name, values...)
+}
+
+// MarkFlagValidValues restricts the named flag to the given values: the
+// various shell completion implementations complete these values, and your
+// command reports an error, with suggestions, if invoked with any other value.
+func MarkFlagValidValues(flags *pflag.FlagSet, name string, values ...string) error {
+	return flags.SetAnnotation(name, FlagValidValuesAnnotation, values)
+}
+
 // MarkFlagDirname instructs the various shell completion implementations to
 // limit completions for the named flag to directory names.
 func (c *Command) MarkFlagDirname(name string) error {
@@ -0,0 +1,197 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// These match the errors returned by pflag when parsing unknown flags.
+var (
+	unknownFlagErrRegexp      = regexp.MustCompile(`^unknown flag: --(.+)$`)
+	unknownShorthandErrRegexp = regexp.MustCompile(`^unknown shorthand flag: '(.)' in -(.+)$`)
+)
+
+// suggestionsEnabled returns whether suggestions should be computed and makes
+// sure SuggestionsMinimumDistance has a usable value.
+func (c *Command) suggestionsEnabled() bool {
+	if c.DisableSuggestions {
+		return false
+	}
+	if c.SuggestionsMinimumDistance <= 0 {
+		c.SuggestionsMinimumDistance = 2
+	}
+	return true
+}
+
+// formatSuggestions formats suggestions the same way for commands, flags and values.
+func formatSuggestions(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n" + Message(MsgDidYouMean) + "\n")
+	for _, s := range suggestions {
+		_, _ = fmt.Fprintf(&sb, "\t%v\n", s)
+	}
+	return sb.String()
+}
+
+// suggestionsFrom returns the candidates that are within the Levenshtein distance
+// of typed, or that typed is a prefix of.
+func suggestionsFrom(typed string, candidates []string, minDistance int) []string {
+	suggestions := []string{}
+	for _, candidate := range candidates {
+		suggestByLevenshtein := ld(typed, candidate, true) <= minDistance
+		suggestByPrefix := strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(typed))
+		if (suggestByLevenshtein || suggestByPrefix) && !stringInSlice(candidate, suggestions) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// FlagSuggestionsFor provides suggestions for an unknown flag, as typed on the command-line
+// (e.g. "--verbos" or "-V"). The local, persistent and inherited flags of the command which
+// are not hidden or deprecated are considered. Suggestions include the leading dashes.
+func (c *Command) FlagSuggestionsFor(typedFlag string) []string {
+	c.mergePersistentFlags()
+	if c.SuggestionsMinimumDistance <= 0 {
+		c.SuggestionsMinimumDistance = 2
+	}
+
+	var names, shorthands []string
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		if f.Hidden || len(f.Deprecated) > 0 {
+			return
+		}
+		names = append(names, f.Name)
+		if len(f.Shorthand) > 0 && len(f.ShorthandDeprecated) == 0 {
+			shorthands = append(shorthands, f.Shorthand)
+		}
+	})
+
+	suggestions := []string{}
+	switch {
+	case strings.HasPrefix(typedFlag, "--"):
+		name := strings.SplitN(typedFlag[2:], "=", 2)[0]
+		for _, s := range suggestionsFrom(name, names, c.SuggestionsMinimumDistance) {
+			suggestions = append(suggestions, "--"+s)
+		}
+	case strings.HasPrefix(typedFlag, "-") && len(typedFlag) > 2:
+		// A long flag typed with a single dash, e.g. -verbose
+		name := strings.SplitN(typedFlag[1:], "=", 2)[0]
+		for _, s := range suggestionsFrom(name, names, c.SuggestionsMinimumDistance) {
+			suggestions = append(suggestions, "--"+s)
+		}
+	case strings.HasPrefix(typedFlag, "-") && len(typedFlag) == 2:
+		// The Levenshtein distance is meaningless for a single character:
+		// only suggest shorthands with a different case and long flags starting
+		// with that character.
+		for _, s := range shorthands {
+			if s != typedFlag[1:] && strings.EqualFold(s, typedFlag[1:]) {
+				suggestions = append(suggestions, "-"+s)
+			}
+		}
+		for _, name := range names {
+			if strings.HasPrefix(strings.ToLower(name), strings.ToLower(typedFlag[1:])) {
+				suggestions = append(suggestions, "--"+name)
+			}
+		}
+	}
+	return suggestions
+}
+
+// flagErrorWithSuggestions adds suggestions to the error returned by pflag
+// when an unknown flag is found on the command-line.
+func (c *Command) flagErrorWithSuggestions(err error) error {
+	if !c.suggestionsEnabled() {
+		return err
+	}
+
+	var suggestions []string
+	if m := unknownFlagErrRegexp.FindStringSubmatch(err.Error()); m != nil {
+		suggestions = c.FlagSuggestionsFor("--" + m[1])
+	} else if m := unknownShorthandErrRegexp.FindStringSubmatch(err.Error()); m != nil {
+		if len(m[2]) > 1 {
+			// Probably a long flag typed with a single dash.
+			suggestions = c.FlagSuggestionsFor("-" + m[2])
+		}
+		if len(suggestions) == 0 {
+			suggestions = c.FlagSuggestionsFor("-" + m[1])
+		}
+	}
+	if len(suggestions) == 0 {
+		return err
+	}
+	return &errorWithSuggestions{err: err, suggestions: formatSuggestions(suggestions)}
+}
+
+// errorWithSuggestions adds suggestions to an error while keeping it
+// available to errors.Is and errors.As.
+type errorWithSuggestions struct {
+	err         error
+	suggestions string
+}
+
+func (e *errorWithSuggestions) Error() string {
+	return e.err.Error() + e.suggestions
+}
+
+func (e *errorWithSuggestions) Unwrap() error {
+	return e.err
+}
+
+// findValueSuggestions returns the formatted suggestions for a value
+// which is not one of the valid ones.
+func (c *Command) findValueSuggestions(value string, validValues []string) string {
+	if !c.suggestionsEnabled() {
+		return ""
+	}
+	return formatSuggestions(suggestionsFrom(value, validValues, c.SuggestionsMinimumDistance))
+}
+
+// ValidateFlagValues validates that the flags marked with MarkFlagValidValues
+// were only given one of their valid values and returns an error otherwise.
+func (c *Command) ValidateFlagValues() error {
+	if c.DisableFlagParsing {
+		return nil
+	}
+
+	var err error
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		validValues, found := f.Annotations[FlagValidValuesAnnotation]
+		if !found || !f.Changed || err != nil {
+			return
+		}
+		values := []string{f.Value.String()}
+		if sliceValue, ok := f.Value.(SliceValue); ok {
+			values = sliceValue.GetSlice()
+		}
+		for _, v := range values {
+			if !stringInSlice(v, validValues) {
+				err = errors.New(messagef(MsgInvalidFlagValue, v, f.Name, strings.Join(validValues, ", ")) +
+					c.findValueSuggestions(v, validValues))
+				return
+			}
+		}
+	})
+	return err
+}
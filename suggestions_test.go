@@ -0,0 +1,150 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newSuggestionsTestCommands() (*Command, *Command) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().StringP("output", "o", "", "output file")
+	childCmd.Flags().String("secret", "", "hidden flag")
+	_ = childCmd.Flags().MarkHidden("secret")
+	childCmd.Flags().String("format", "text", "output format")
+	_ = childCmd.MarkFlagValidValues("format", "text", "json", "yaml")
+	childCmd.Flags().StringSlice("fields", nil, "fields to show")
+	_ = childCmd.MarkFlagValidValues("fields", "name", "size", "date")
+	rootCmd.AddCommand(childCmd)
+	return rootCmd, childCmd
+}
+
+func TestFlagSuggestions(t *testing.T) {
+	testCases := []struct {
+		args       []string
+		suggestion string
+	}{
+		{[]string{"child", "--outptu", "f"}, "--output"},
+		{[]string{"child", "--verbos"}, "--verbose"},
+		{[]string{"child", "--out=f"}, "--output"},
+		{[]string{"child", "-verbose"}, "--verbose"},
+		{[]string{"child", "-V"}, "-v"},
+		{[]string{"child", "--secre"}, ""},
+		{[]string{"--outptu"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			rootCmd, _ := newSuggestionsTestCommands()
+			_, err := executeCommand(rootCmd, tc.args...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tc.suggestion == "" {
+				checkStringOmits(t, err.Error(), "Did you mean this?")
+				return
+			}
+			checkStringContains(t, err.Error(), fmt.Sprintf("\n\nDid you mean this?\n\t%s\n", tc.suggestion))
+		})
+	}
+}
+
+func TestFlagSuggestionsDisabled(t *testing.T) {
+	rootCmd, childCmd := newSuggestionsTestCommands()
+	childCmd.DisableSuggestions = true
+
+	_, err := executeCommand(rootCmd, "child", "--outptu", "f")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if err.Error() != "unknown flag: --outptu" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestFlagSuggestionsFor(t *testing.T) {
+	_, childCmd := newSuggestionsTestCommands()
+
+	got := childCmd.FlagSuggestionsFor("--form")
+	if len(got) != 1 || got[0] != "--format" {
+		t.Errorf("Expected [--format], got %v", got)
+	}
+	got = childCmd.FlagSuggestionsFor("-O")
+	if len(got) != 2 || got[0] != "-o" || got[1] != "--output" {
+		t.Errorf("Expected [-o --output], got %v", got)
+	}
+}
+
+func TestFlagValidValues(t *testing.T) {
+	rootCmd, _ := newSuggestionsTestCommands()
+	if _, err := executeCommand(rootCmd, "child", "--format", "json"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	rootCmd, _ = newSuggestionsTestCommands()
+	_, err := executeCommand(rootCmd, "child", "--format", "jsno")
+	expected := `invalid value "jsno" for flag "--format", valid values are: text, json, yaml` + "\n\nDid you mean this?\n\tjson\n"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+
+	rootCmd, _ = newSuggestionsTestCommands()
+	_, err = executeCommand(rootCmd, "child", "--fields", "name,szie")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringContains(t, err.Error(), `invalid value "szie" for flag "--fields"`)
+	checkStringContains(t, err.Error(), "Did you mean this?\n\tsize\n")
+}
+
+func TestFlagValidValuesCompletion(t *testing.T) {
+	rootCmd, _ := newSuggestionsTestCommands()
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "child", "--format", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"text",
+		"json",
+		"yaml",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+}
+
+func TestOnlyValidArgsSuggestions(t *testing.T) {
+	c := &Command{
+		Use:       "c",
+		Args:      OnlyValidArgs,
+		ValidArgs: []string{"start\tstart the server", "stop", "status"},
+		Run:       emptyRun,
+	}
+	_, err := executeCommand(c, "stpo")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected := "invalid argument \"stpo\" for \"c\"\n\nDid you mean this?\n\tstop\n"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
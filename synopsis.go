@@ -0,0 +1,167 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Argument describes a positional argument of a command, for its synopsis.
+type Argument struct {
+	// Name is the name of the argument as shown in the synopsis, e.g. "FILE".
+	Name string
+	// Optional is true if the argument can be omitted.
+	Optional bool
+	// Variadic is true if the argument can be repeated.
+	Variadic bool
+}
+
+// String returns the argument as shown in the synopsis: NAME, [NAME], NAME... or [NAME...].
+func (a Argument) String() string {
+	s := a.Name
+	if a.Variadic {
+		s += "..."
+	}
+	if a.Optional {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// Synopsis returns the synopsis of the command, built from its flags and arguments:
+// the required flags are shown explicitly, the flags marked with MarkFlagsOneRequired
+// as (--file | --url), those marked with MarkFlagsMutuallyExclusive as [--json | --yaml],
+// those marked with MarkFlagsRequiredTogether as [--user string --password string], and
+// the other flags as [flags]. The positional arguments come from Arguments if set, from
+// Use otherwise, e.g.:
+//
+//	app get --namespace string (--file string | --url string) [--json | --yaml] [flags] TYPE [NAME...]
+//
+// If DisableFlagsInUseLine is set, the flags are omitted.
+func (c *Command) Synopsis() string {
+	parts := []string{c.DisplayName()}
+	if c.HasParent() {
+		parts[0] = c.parent.CommandPath() + " " + parts[0]
+	}
+	if !c.DisableFlagsInUseLine {
+		parts = append(parts, c.synopsisFlags()...)
+	}
+	if len(c.Arguments) > 0 {
+		for _, arg := range c.Arguments {
+			parts = append(parts, arg.String())
+		}
+	} else if words := strings.Fields(c.Use); len(words) > 1 {
+		for _, word := range words[1:] {
+			if word != "[flags]" {
+				parts = append(parts, word)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// synopsisFlags returns the parts of the synopsis describing the flags of c.
+func (c *Command) synopsisFlags() []string {
+	// Include the persistent flags of the parents, as ValidateRequiredFlags does
+	c.mergePersistentFlags()
+	flags := c.Flags()
+	shown := map[string]bool{}
+	var parts []string
+
+	flags.VisitAll(func(f *flag.Flag) {
+		if isSynopsisFlag(f) && isRequiredFlag(f) {
+			parts = append(parts, synopsisFlag(f))
+			shown[f.Name] = true
+		}
+	})
+
+	groups := c.flagGroupInfos()
+	oneRequired := map[string]bool{}
+	for _, kind := range []string{FlagGroupOneRequired, FlagGroupMutuallyExclusive, FlagGroupRequiredTogether} {
+		for _, group := range groups {
+			if group.Kind != kind {
+				continue
+			}
+			key := strings.Join(group.Flags, " ")
+			if kind == FlagGroupMutuallyExclusive && oneRequired[key] {
+				// Already shown as a group where exactly one flag is required
+				continue
+			}
+			var members []*flag.Flag
+			for _, name := range group.Flags {
+				f := flags.Lookup(name)
+				if f == nil || shown[name] {
+					members = nil
+					break
+				}
+				if isSynopsisFlag(f) {
+					members = append(members, f)
+				}
+			}
+			if len(members) < 2 {
+				continue
+			}
+			var memberParts []string
+			for _, f := range members {
+				memberParts = append(memberParts, synopsisFlag(f))
+				shown[f.Name] = true
+			}
+			switch kind {
+			case FlagGroupOneRequired:
+				oneRequired[key] = true
+				parts = append(parts, "("+strings.Join(memberParts, " | ")+")")
+			case FlagGroupMutuallyExclusive:
+				parts = append(parts, "["+strings.Join(memberParts, " | ")+"]")
+			default:
+				parts = append(parts, "["+strings.Join(memberParts, " ")+"]")
+			}
+		}
+	}
+
+	hasOthers := false
+	flags.VisitAll(func(f *flag.Flag) {
+		hasOthers = hasOthers || (isSynopsisFlag(f) && !shown[f.Name])
+	})
+	if hasOthers {
+		parts = append(parts, "[flags]")
+	}
+	return parts
+}
+
+// isSynopsisFlag returns whether the flag is shown in the synopsis.
+func isSynopsisFlag(f *flag.Flag) bool {
+	return !f.Hidden && len(f.Deprecated) == 0
+}
+
+// isRequiredFlag returns whether the flag was marked with MarkFlagRequired.
+func isRequiredFlag(f *flag.Flag) bool {
+	required, found := f.Annotations[BashCompOneRequiredFlag]
+	return found && len(required) > 0 && required[0] == "true"
+}
+
+// synopsisFlag returns the flag as shown in the synopsis, e.g. --name string.
+func synopsisFlag(f *flag.Flag) string {
+	varname, _ := flag.UnquoteUsage(f)
+	switch {
+	case varname == "":
+		return "--" + f.Name
+	case f.NoOptDefVal != "":
+		return "--" + f.Name + "[=" + varname + "]"
+	default:
+		return "--" + f.Name + " " + varname
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSynopsis(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	getCmd := &Command{Use: "get TYPE [NAME...]", Run: emptyRun}
+	getCmd.Flags().String("namespace", "", "the namespace")
+	_ = getCmd.MarkFlagRequired("namespace")
+	getCmd.Flags().Bool("json", false, "JSON output")
+	getCmd.Flags().Bool("yaml", false, "YAML output")
+	getCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	getCmd.Flags().String("file", "", "read from `path`")
+	getCmd.Flags().String("url", "", "read from url")
+	getCmd.MarkFlagsOneRequired("file", "url")
+	getCmd.Flags().Int("limit", 0, "maximum number of results")
+	rootCmd.AddCommand(getCmd)
+
+	expected := "app get --namespace string (--file path | --url string) [--json | --yaml] [flags] TYPE [NAME...]"
+	if got := getCmd.Synopsis(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSynopsisPersistentRequiredFlag(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().String("token", "", "the API token")
+	_ = rootCmd.MarkPersistentFlagRequired("token")
+	getCmd := &Command{Use: "get TYPE", Run: emptyRun}
+	rootCmd.AddCommand(getCmd)
+
+	expected := "app get --token string TYPE"
+	if got := getCmd.Synopsis(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSynopsisEmptyUse(t *testing.T) {
+	for _, use := range []string{"", "  "} {
+		cmd := &Command{Use: use, Run: emptyRun}
+		cmd.Flags().Bool("json", false, "JSON output")
+		if got := cmd.Synopsis(); strings.TrimSpace(got) != "[flags]" {
+			t.Errorf("Use %q: expected only the flags, got %q", use, got)
+		}
+	}
+}
+
+func TestSynopsisArguments(t *testing.T) {
+	cmd := &Command{
+		Use: "cp",
+		Arguments: []Argument{
+			{Name: "SOURCE", Variadic: true},
+			{Name: "DEST"},
+			{Name: "MODE", Optional: true},
+			{Name: "EXTRA", Optional: true, Variadic: true},
+		},
+		Run: emptyRun,
+	}
+	cmd.Flags().String("hidden", "", "hidden")
+	_ = cmd.Flags().MarkHidden("hidden")
+	expected := "cp SOURCE... DEST [MODE] [EXTRA...]"
+	if got := cmd.Synopsis(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSynopsisGroups(t *testing.T) {
+	cmd := &Command{Use: "login [flags]", Run: emptyRun}
+	cmd.Flags().String("user", "", "user name")
+	cmd.Flags().String("password", "", "password")
+	cmd.MarkFlagsRequiredTogether("user", "password")
+	cmd.Flags().Bool("json", false, "JSON output")
+	cmd.Flags().Bool("yaml", false, "YAML output")
+	cmd.MarkFlagsOneRequired("json", "yaml")
+	cmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	cmd.Flags().String("color", "", "color `mode`")
+	cmd.Flags().Lookup("color").NoOptDefVal = "auto"
+
+	expected := "login (--json | --yaml) [--user string --password string] [flags]"
+	if got := cmd.Synopsis(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	cmd.DisableFlagsInUseLine = true
+	if got := cmd.Synopsis(); got != "login" {
+		t.Errorf("expected %q, got %q", "login", got)
+	}
+	if got := synopsisFlag(cmd.Flags().Lookup("color")); got != "--color[=mode]" {
+		t.Errorf("expected %q, got %q", "--color[=mode]", got)
+	}
+}
+
+func TestUseSynopsis(t *testing.T) {
+	rootCmd := &Command{Use: "app", UseSynopsis: true}
+	getCmd := &Command{Use: "get NAME", Run: emptyRun}
+	getCmd.Flags().String("namespace", "", "the namespace")
+	_ = getCmd.MarkFlagRequired("namespace")
+	rootCmd.AddCommand(getCmd)
+
+	output, err := executeCommand(rootCmd, "get", "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Usage:\n  app get --namespace string [flags] NAME\n")
+}
@@ -0,0 +1,128 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultHelpWidth is the width of the help written to a terminal of unknown width.
+const defaultHelpWidth = 80
+
+// isTerminal returns whether the given stream is a terminal.
+// It is a variable so that tests can override it.
+var isTerminal = func(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns the width of the terminal the given stream is
+// connected to, or 0 if it cannot be determined.
+// It is a variable so that tests can override it.
+var terminalWidth = func(stream interface{}) int {
+	if f, ok := stream.(*os.File); ok {
+		if width := fileTerminalWidth(f); width > 0 {
+			return width
+		}
+	}
+	width, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return width
+}
+
+// HelpWidth returns the width to which the help and usage of the command are wrapped.
+// It is the value of the environment variable <PROGRAM>_HELP_WIDTH (or COBRA_HELP_WIDTH) if set,
+// or the width of the terminal if the output is a terminal, 80 if it cannot be determined.
+// It is 0, which disables wrapping, if the output is not a terminal, so that piped or
+// redirected help is left as written.
+func (c *Command) HelpWidth() int {
+	if v := getEnvConfig(c, "HELP_WIDTH"); v != "" {
+		if width, err := strconv.Atoi(v); err == nil && width >= 0 {
+			return width
+		}
+	}
+	out := c.helpOutput
+	if out == nil {
+		out = c.OutOrStdout()
+	}
+	if isTerminal(out) {
+		if width := terminalWidth(out); width > 0 {
+			return width
+		}
+		return defaultHelpWidth
+	}
+	return 0
+}
+
+// wrapText wraps the lines of s which are longer than width characters at word
+// boundaries. Lines starting with a space or a tab, such as code blocks, are
+// left untouched. A width of 0 disables wrapping.
+func wrapText(width int, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		lines[i] = wrapLine(0, width, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapIndent wraps s to lines of at most width characters, with a hanging
+// indentation: the first line is assumed to be already indented by indent
+// characters, and the following lines are indented with as many spaces.
+// If there is not enough room left for the text, it is not wrapped.
+// A width of 0 disables wrapping.
+func wrapIndent(indent, width int, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = wrapLine(indent, width, line)
+	}
+	return strings.Join(lines, "\n"+strings.Repeat(" ", indent))
+}
+
+// minWrapWidth is the minimum room for the text under which it is not wrapped.
+const minWrapWidth = 24
+
+// wrapLine wraps a single line, see wrapIndent.
+func wrapLine(indent, width int, line string) string {
+	if width <= 0 || width-indent < minWrapWidth || indent+utf8.RuneCountInString(line) <= width {
+		return line
+	}
+
+	var sb strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(line) {
+		wordLen := utf8.RuneCountInString(word)
+		switch {
+		case lineLen == 0:
+		case indent+lineLen+1+wordLen > width:
+			sb.WriteString("\n" + strings.Repeat(" ", indent))
+			lineLen = 0
+		default:
+			sb.WriteByte(' ')
+			lineLen++
+		}
+		sb.WriteString(word)
+		lineLen += wordLen
+	}
+	return sb.String()
+}
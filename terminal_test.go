@@ -0,0 +1,158 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// setTerminalWidth makes every stream look like a terminal of the given width for the duration of the test.
+func setTerminalWidth(t *testing.T, width int) {
+	setTerminal(t, true)
+	orig := terminalWidth
+	terminalWidth = func(interface{}) int { return width }
+	t.Cleanup(func() { terminalWidth = orig })
+}
+
+func TestWrapText(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		width    int
+		input    string
+		expected string
+	}{
+		{"short lines", 30, "one two\nthree", "one two\nthree"},
+		{"long line", 30, "the quick brown fox jumps over the lazy dog", "the quick brown fox jumps over\nthe lazy dog"},
+		{"indented line", 30, "    the quick brown fox jumps over the lazy dog", "    the quick brown fox jumps over the lazy dog"},
+		{"long word", 30, "a-very-long-word-which-cannot-be-wrapped ok", "a-very-long-word-which-cannot-be-wrapped\nok"},
+		{"disabled", 0, "the quick brown fox jumps over the lazy dog", "the quick brown fox jumps over the lazy dog"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := wrapText(tc.width, tc.input); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWrapIndent(t *testing.T) {
+	got := wrapIndent(10, 40, "the quick brown fox jumps over the lazy dog")
+	expected := "the quick brown fox jumps over\n          the lazy dog"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// Not enough room to wrap
+	input := "the quick brown fox jumps over the lazy dog"
+	if got := wrapIndent(30, 40, input); got != input {
+		t.Errorf("expected %q, got %q", input, got)
+	}
+}
+
+func TestHelpWidth(t *testing.T) {
+	c := &Command{Use: "root", Run: emptyRun}
+	c.SetOut(new(bytes.Buffer))
+	if width := c.HelpWidth(); width != 0 {
+		t.Errorf("expected no wrapping when not writing to a terminal, got %d", width)
+	}
+
+	setTerminalWidth(t, 0)
+	if width := c.HelpWidth(); width != 80 {
+		t.Errorf("expected a width of 80 for a terminal of unknown width, got %d", width)
+	}
+
+	setTerminalWidth(t, 120)
+	if width := c.HelpWidth(); width != 120 {
+		t.Errorf("expected the width of the terminal, got %d", width)
+	}
+
+	setEnv(t, "COBRA_HELP_WIDTH", "100")
+	if width := c.HelpWidth(); width != 100 {
+		t.Errorf("expected the width of COBRA_HELP_WIDTH, got %d", width)
+	}
+
+	setEnv(t, "ROOT_HELP_WIDTH", "0")
+	if width := c.HelpWidth(); width != 0 {
+		t.Errorf("expected the width of ROOT_HELP_WIDTH, got %d", width)
+	}
+}
+
+func newWrapTestCommand() *Command {
+	rootCmd := &Command{
+		Use:   "root",
+		Short: "root command",
+		Long:  "This is a long description of the root command which does not fit on a single line of a narrow terminal.",
+		Run:   emptyRun,
+	}
+	rootCmd.Flags().String("format", "", "the format of the output, which can be one of text, json or yaml")
+	rootCmd.AddCommand(&Command{Use: "child", Short: "a child command with a short description which is quite long", Run: emptyRun})
+	return rootCmd
+}
+
+func TestHelpIsWrapped(t *testing.T) {
+	setTerminalWidth(t, 50)
+
+	output, err := executeCommand(newWrapTestCommand(), "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) > 50 {
+			t.Errorf("Line longer than 50 characters: %q", line)
+		}
+	}
+	checkStringContains(t, output, "This is a long description of the root command\nwhich")
+	checkStringContains(t, output, "  child       a child command with a short\n              description which is quite long\n")
+	checkStringContains(t, output, "--format string   the format of the\n"+strings.Repeat(" ", 24)+"output, which can be\n")
+}
+
+func TestHelpIsNotWrappedWhenNotATerminal(t *testing.T) {
+	setTerminal(t, false)
+
+	c := newWrapTestCommand()
+	output, err := executeCommand(c, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, c.Long+"\n")
+	checkStringContains(t, output, "  child       a child command with a short description which is quite long\n")
+}
+
+func TestWrappedUsageFuncMatchesTemplate(t *testing.T) {
+	for _, width := range []int{0, 50, 80} {
+		setTerminalWidth(t, width)
+		c := newWrapTestCommand()
+
+		var funcOutput, tmplOutput bytes.Buffer
+		assertNoErr(t, defaultUsageFunc(&funcOutput, c))
+		assertNoErr(t, tmpl(defaultUsageTemplate).fn(&tmplOutput, c))
+		if funcOutput.String() != tmplOutput.String() {
+			t.Errorf("width %d: defaultUsageFunc and defaultUsageTemplate differ:\n%q\n%q", width, funcOutput.String(), tmplOutput.String())
+		}
+
+		funcOutput.Reset()
+		tmplOutput.Reset()
+		assertNoErr(t, defaultHelpFunc(&funcOutput, c))
+		assertNoErr(t, tmpl(defaultHelpTemplate).fn(&tmplOutput, c))
+		if funcOutput.String() != tmplOutput.String() {
+			t.Errorf("width %d: defaultHelpFunc and defaultHelpTemplate differ:\n%q\n%q", width, funcOutput.String(), tmplOutput.String())
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Theme defines the styles of the elements of the default help, usage and
+// error output. Each style is a list of ANSI SGR parameters separated by
+// semicolons, e.g. "1;34" for bold blue. An empty style leaves the element as is.
+type Theme struct {
+	// Heading is the style of section headings such as "Usage:" and "Flags:".
+	Heading string
+	// Command is the style of the names of commands.
+	Command string
+	// Flag is the style of the names of flags.
+	Flag string
+	// ErrorPrefix is the style of the prefix of error messages.
+	ErrorPrefix string
+}
+
+// DefaultTheme is a theme that can be set with SetTheme to enable colors with the
+// default styles.
+var DefaultTheme = Theme{
+	Heading:     "1",
+	Command:     "36",
+	Flag:        "33",
+	ErrorPrefix: "1;31",
+}
+
+// ansiRegexp matches the ANSI escape sequences setting graphic rendition.
+var ansiRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// flagUsageRegexp matches the names of the flag at the start of a line of flag usages.
+var flagUsageRegexp = regexp.MustCompile(`^( {2}| {6})(-\S, )?--\S+`)
+
+// StripANSI removes the ANSI escape sequences setting colors and styles from s.
+func StripANSI(s string) string {
+	return ansiRegexp.ReplaceAllString(s, "")
+}
+
+// SetTheme sets the theme used for the help, usage and errors of the command and its children.
+// Colors are only used once a theme is set. A nil theme disables colors again.
+func (c *Command) SetTheme(theme *Theme) {
+	c.theme = theme
+	c.themeSet = true
+}
+
+// Theme returns the theme set with SetTheme for this command or a parent.
+// It returns nil if no theme is set, or if colors are disabled with SetTheme(nil).
+func (c *Command) Theme() *Theme {
+	if c.themeSet {
+		return c.theme
+	}
+	if c.HasParent() {
+		return c.Parent().Theme()
+	}
+	return nil
+}
+
+// colorEnabled returns whether colors should be used when writing to the given stream:
+// the stream must be a terminal, and neither NO_COLOR nor <PROGRAM>_NO_COLOR, or
+// COBRA_NO_COLOR, may be set.
+func (c *Command) colorEnabled(stream interface{}) bool {
+	if os.Getenv("NO_COLOR") != "" || getEnvConfig(c, "NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(stream)
+}
+
+// helpStyle applies style to s if colors are enabled for the help.
+func (c *Command) helpStyle(style, s string) string {
+	out := c.helpOutput
+	if out == nil {
+		out = c.OutOrStdout()
+	}
+	if style == "" || !c.colorEnabled(out) {
+		return s
+	}
+	return applyStyle(style, s)
+}
+
+func applyStyle(style, s string) string {
+	if style == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + style + "m" + s + "\x1b[0m"
+}
+
+// StyleHeading returns s with the heading style of the theme, if colors are enabled.
+func (c *Command) StyleHeading(s string) string {
+	if theme := c.Theme(); theme != nil {
+		return c.helpStyle(theme.Heading, s)
+	}
+	return s
+}
+
+// StyleCommand returns s with the command style of the theme, if colors are enabled.
+func (c *Command) StyleCommand(s string) string {
+	if theme := c.Theme(); theme != nil {
+		return c.helpStyle(theme.Command, s)
+	}
+	return s
+}
+
+// styleFlag returns s with the flag style of the theme, if colors are enabled.
+func (c *Command) styleFlag(s string) string {
+	if theme := c.Theme(); theme != nil {
+		return c.helpStyle(theme.Flag, s)
+	}
+	return s
+}
+
+// StyleFlags returns the flag usages, as returned by FlagUsages, with the flag
+// names in the flag style of the theme, if colors are enabled.
+func (c *Command) StyleFlags(usages string) string {
+	theme := c.Theme()
+	if theme == nil || c.helpStyle(theme.Flag, "-") == "-" {
+		return usages
+	}
+	lines := strings.Split(usages, "\n")
+	for i, line := range lines {
+		lines[i] = flagUsageRegexp.ReplaceAllStringFunc(line, func(m string) string {
+			names := strings.TrimLeft(m, " \t")
+			return m[:len(m)-len(names)] + applyStyle(theme.Flag, names)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StyleErrorPrefix returns s with the error prefix style of the theme, if colors
+// are enabled for the error output.
+func (c *Command) StyleErrorPrefix(s string) string {
+	theme := c.Theme()
+	if theme == nil || !c.colorEnabled(c.ErrOrStderr()) {
+		return s
+	}
+	return applyStyle(theme.ErrorPrefix, s)
+}
@@ -0,0 +1,163 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// setColorTerminal makes every stream look like a terminal with colors enabled.
+func setColorTerminal(t *testing.T) {
+	setTerminal(t, true)
+	unsetEnv(t, "NO_COLOR")
+	unsetEnv(t, "ROOT_NO_COLOR")
+	unsetEnv(t, "COBRA_NO_COLOR")
+	setEnv(t, "TERM", "xterm")
+}
+
+func newThemeTestCommand() *Command {
+	rootCmd := &Command{Use: "root", Short: "the root", Run: emptyRun}
+	rootCmd.SetTheme(&DefaultTheme)
+	rootCmd.Flags().StringP("format", "f", "", "output format")
+	rootCmd.AddCommand(&Command{Use: "child", Short: "the child", Run: emptyRun})
+	return rootCmd
+}
+
+func TestThemeHelp(t *testing.T) {
+	setColorTerminal(t)
+
+	output, err := executeCommand(newThemeTestCommand(), "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "\x1b[1mUsage:\x1b[0m\n")
+	checkStringContains(t, output, "\x1b[1mAvailable Commands:\x1b[0m\n")
+	checkStringContains(t, output, "  \x1b[36mchild      \x1b[0m the child\n")
+	checkStringContains(t, output, "  \x1b[33m-f, --format\x1b[0m string   output format\n")
+	checkStringContains(t, output, "  \x1b[33m-h, --help\x1b[0m            help for root\n")
+}
+
+func TestThemeNotSetByDefault(t *testing.T) {
+	setColorTerminal(t)
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().StringP("format", "f", "", "output format")
+	if rootCmd.Theme() != nil {
+		t.Errorf("Expected no theme by default, got %v", rootCmd.Theme())
+	}
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "\x1b[")
+
+	output, err = executeCommand(rootCmd, "--unknown")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringOmits(t, output, "\x1b[")
+}
+
+func TestThemeCustom(t *testing.T) {
+	setColorTerminal(t)
+
+	rootCmd := newThemeTestCommand()
+	rootCmd.SetTheme(&Theme{Heading: "4;35"})
+	output, err := executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "\x1b[4;35mUsage:\x1b[0m\n")
+	checkStringOmits(t, output, "\x1b[33m")
+}
+
+func TestThemeErrorPrefix(t *testing.T) {
+	setColorTerminal(t)
+
+	output, err := executeCommand(newThemeTestCommand(), "--unknown")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringContains(t, output, "\x1b[1;31mError:\x1b[0m unknown flag: --unknown\n")
+}
+
+func TestThemeDisabled(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		setup func(t *testing.T, c *Command)
+	}{
+		{"not a terminal", func(t *testing.T, c *Command) { setTerminal(t, false); unsetEnv(t, "NO_COLOR") }},
+		{"NO_COLOR", func(t *testing.T, c *Command) { setEnv(t, "NO_COLOR", "1") }},
+		{"ROOT_NO_COLOR", func(t *testing.T, c *Command) { setEnv(t, "ROOT_NO_COLOR", "true") }},
+		{"COBRA_NO_COLOR", func(t *testing.T, c *Command) { setEnv(t, "COBRA_NO_COLOR", "1") }},
+		{"dumb terminal", func(t *testing.T, c *Command) { setEnv(t, "TERM", "dumb") }},
+		{"nil theme", func(t *testing.T, c *Command) { c.SetTheme(nil) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			setColorTerminal(t)
+			rootCmd := newThemeTestCommand()
+			tc.setup(t, rootCmd)
+
+			output, err := executeCommand(rootCmd, "--help")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			checkStringOmits(t, output, "\x1b[")
+
+			output, err = executeCommand(newThemeTestCommandWithSetup(t, tc.setup), "--unknown")
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			checkStringOmits(t, output, "\x1b[")
+		})
+	}
+}
+
+func newThemeTestCommandWithSetup(t *testing.T, setup func(t *testing.T, c *Command)) *Command {
+	c := newThemeTestCommand()
+	setup(t, c)
+	return c
+}
+
+func TestThemedUsageFuncMatchesTemplate(t *testing.T) {
+	setColorTerminal(t)
+
+	c := newThemeTestCommand()
+	c.AddGroup(&Group{ID: "group", Title: "Group:"})
+	c.AddCommand(&Command{Use: "grouped", GroupID: "group", Run: emptyRun})
+	c.PersistentFlags().Bool("global", false, "global flag")
+	c.SetOut(new(bytes.Buffer))
+
+	for _, cmd := range []*Command{c, c.Commands()[0]} {
+		cmd.mergePersistentFlags()
+		var funcOutput, tmplOutput bytes.Buffer
+		assertNoErr(t, defaultUsageFunc(&funcOutput, cmd))
+		assertNoErr(t, tmpl(defaultUsageTemplate).fn(&tmplOutput, cmd))
+		if funcOutput.String() != tmplOutput.String() {
+			t.Errorf("defaultUsageFunc and defaultUsageTemplate differ:\n%q\n%q", funcOutput.String(), tmplOutput.String())
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	s := fmt.Sprintf("%s and %s", applyStyle("1;31", "red"), applyStyle("4", "underlined"))
+	if got := StripANSI(s); got != "red and underlined" {
+		t.Errorf("Unexpected result: %q", got)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	helpTreeFlagName  = "tree"
+	helpAllFlagName   = "all"
+	helpDepthFlagName = "depth"
+	helpFlagsFlagName = "flags"
+)
+
+// TreeOptions are the options of WriteTree.
+type TreeOptions struct {
+	// Depth limits the number of levels of subcommands printed. Zero means no limit.
+	Depth int
+	// Flags includes the flags of each command, below it.
+	Flags bool
+}
+
+// treeLine is a line of the tree, made of a label and an optional description.
+type treeLine struct {
+	indent int
+	label  string
+	// suffix follows the label without being styled, e.g. the type of a flag.
+	suffix      string
+	description string
+	kind        treeLineKind
+}
+
+type treeLineKind int
+
+const (
+	treeLineCommand treeLineKind = iota
+	treeLineGroup
+	treeLineFlag
+)
+
+// WriteTree writes the tree of the command and its available subcommands,
+// with their short descriptions. Subcommands are indented below their parent
+// and listed by group. Hidden and deprecated commands and flags are omitted.
+func (c *Command) WriteTree(w io.Writer, opts TreeOptions) error {
+	var lines []treeLine
+	var walk func(cmd *Command, level int)
+	walk = func(cmd *Command, level int) {
+		indent := 2 * level
+		lines = append(lines, treeLine{indent: indent, label: cmd.Name(), description: cmd.DisplayShort(), kind: treeLineCommand})
+		if opts.Flags {
+			cmd.LocalFlags().VisitAll(func(f *flag.Flag) {
+				if f.Hidden || len(f.Deprecated) > 0 {
+					return
+				}
+				label, suffix := treeFlagLabel(f)
+				lines = append(lines, treeLine{indent: indent + 2, label: label, suffix: suffix, description: cmd.DisplayFlagUsage(f), kind: treeLineFlag})
+			})
+		}
+		if opts.Depth > 0 && level >= opts.Depth {
+			return
+		}
+
+		children := []*Command{}
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				children = append(children, sub)
+			}
+		}
+		for _, group := range cmd.Groups() {
+			var grouped []*Command
+			for _, sub := range children {
+				if sub.GroupID == group.ID {
+					grouped = append(grouped, sub)
+				}
+			}
+			if len(grouped) == 0 {
+				continue
+			}
+			lines = append(lines, treeLine{indent: indent + 2, label: cmd.LocalizedGroupTitle(group), kind: treeLineGroup})
+			for _, sub := range grouped {
+				walk(sub, level+1)
+			}
+		}
+		ungroupedTitle := false
+		for _, sub := range children {
+			if sub.GroupID != "" && cmd.ContainsGroup(sub.GroupID) {
+				continue
+			}
+			if len(cmd.Groups()) > 0 && !ungroupedTitle {
+				lines = append(lines, treeLine{indent: indent + 2, label: Message(MsgAdditionalCommands), kind: treeLineGroup})
+				ungroupedTitle = true
+			}
+			walk(sub, level+1)
+		}
+	}
+	walk(c, 0)
+
+	padding := 0
+	for _, l := range lines {
+		if l.kind != treeLineGroup && l.indent+len(l.label+l.suffix) > padding {
+			padding = l.indent + len(l.label+l.suffix)
+		}
+	}
+	width := c.HelpWidth()
+	for _, l := range lines {
+		var err error
+		indent := strings.Repeat(" ", l.indent)
+		switch l.kind {
+		case treeLineGroup:
+			_, err = fmt.Fprintf(w, "%s%s\n", indent, c.StyleHeading(l.label))
+		case treeLineFlag:
+			suffix := rpad(l.suffix, padding-l.indent-len(l.label))
+			_, err = fmt.Fprintf(w, "%s%s%s  %s\n", indent, c.styleFlag(l.label), suffix, wrapIndent(padding+2, width, l.description))
+		default:
+			label := rpad(l.label, padding-l.indent)
+			_, err = fmt.Fprintf(w, "%s%s  %s\n", indent, c.StyleCommand(label), wrapIndent(padding+2, width, l.description))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treeFlagLabel returns the names of the flag as shown in a tree, e.g. "-d, --depth",
+// and the suffix giving its type, e.g. " int".
+func treeFlagLabel(f *flag.Flag) (string, string) {
+	label := "--" + f.Name
+	if len(f.Shorthand) > 0 && len(f.ShorthandDeprecated) == 0 {
+		label = "-" + f.Shorthand + ", " + label
+	}
+	suffix := ""
+	if varname, _ := flag.UnquoteUsage(f); varname != "" {
+		suffix = " " + varname
+	}
+	return label, suffix
+}
+
+// helpTreeRequested returns whether the tree was requested from the help command.
+func (c *Command) helpTreeRequested() bool {
+	tree, _ := c.Flags().GetBool(helpTreeFlagName)
+	all, _ := c.Flags().GetBool(helpAllFlagName)
+	return tree || all
+}
@@ -0,0 +1,105 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newTreeTestCommands() *Command {
+	rootCmd := &Command{Use: "root", Short: "the root command"}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.AddGroup(&Group{ID: "manage", Title: "Management Commands:"})
+	remoteCmd := &Command{Use: "remote", Short: "manage remote repositories", GroupID: "manage"}
+	addCmd := &Command{Use: "add", Short: "add a remote", Run: emptyRun}
+	addCmd.Flags().Int("depth", 0, "depth of the history")
+	addCmd.Flags().String("hidden", "", "hidden flag")
+	_ = addCmd.Flags().MarkHidden("hidden")
+	remoteCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(
+		remoteCmd,
+		&Command{Use: "clone", Short: "clone a repository", Run: emptyRun},
+		&Command{Use: "secret", Short: "a secret", Hidden: true, Run: emptyRun},
+		&Command{Use: "old", Short: "an old command", Deprecated: "do not use", Run: emptyRun},
+	)
+	return rootCmd
+}
+
+func TestWriteTree(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		opts     TreeOptions
+		expected string
+	}{
+		{"full", TreeOptions{}, "" +
+			"root      the root command\n" +
+			"  Management Commands:\n" +
+			"  remote  manage remote repositories\n" +
+			"    add   add a remote\n" +
+			"  Additional Commands:\n" +
+			"  clone   clone a repository\n"},
+		{"depth", TreeOptions{Depth: 1}, "" +
+			"root      the root command\n" +
+			"  Management Commands:\n" +
+			"  remote  manage remote repositories\n" +
+			"  Additional Commands:\n" +
+			"  clone   clone a repository\n"},
+		{"flags", TreeOptions{Flags: true}, "" +
+			"root               the root command\n" +
+			"  -v, --verbose    verbose output\n" +
+			"  Management Commands:\n" +
+			"  remote           manage remote repositories\n" +
+			"    add            add a remote\n" +
+			"      --depth int  depth of the history\n" +
+			"  Additional Commands:\n" +
+			"  clone            clone a repository\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			assertNoErr(t, newTreeTestCommands().WriteTree(buf, tc.opts))
+			if buf.String() != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestHelpTree(t *testing.T) {
+	output, err := executeCommand(newTreeTestCommands(), "help", "--tree", "remote")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := "" +
+		"remote  manage remote repositories\n" +
+		"  add   add a remote\n"
+	if output != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, output)
+	}
+
+	output, err = executeCommand(newTreeTestCommands(), "help", "--all", "--depth", "1", "--flags")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "root ")
+	checkStringContains(t, output, "--verbose")
+	checkStringContains(t, output, "  remote ")
+	checkStringContains(t, output, "  completion ")
+	checkStringOmits(t, output, "    add")
+	checkStringOmits(t, output, "secret")
+	checkStringOmits(t, output, "old")
+}